@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/internal/bench"
+	"github.com/pandadragoon/grpc-go-course/internal/cli"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
+)
+
+const program = "bench"
+
+func main() {
+	cli.Main(program, []cli.Command{
+		{Name: "run", Summary: "call a method repeatedly and report throughput and latency", Run: runBench},
+		{Name: "methods", Summary: "list the methods that can be benchmarked", Run: runMethods},
+	})
+}
+
+func runBench(args []string) error {
+	fs := cli.NewFlagSet(program, "run")
+	var conn cli.ConnFlags
+	conn.Register(fs, 10*time.Second)
+	var cfg bench.Config
+	fs.IntVar(&cfg.Concurrency, "c", 10, "number of calls in flight at once")
+	fs.Float64Var(&cfg.Rate, "rate", 0, "start at most this many calls per second (0 for no limit)")
+	fs.DurationVar(&cfg.Duration, "d", 10*time.Second, "how long to run (0 to stop after -n calls only)")
+	fs.IntVar(&cfg.Calls, "n", 0, "stop after this many calls (0 to stop after -d only)")
+	data := fs.String("data", "{}", "request as JSON, a template with .Call, .Message, rand, randf, str and pick")
+	first := fs.String("first", "", "JSON template of the first message of client and bidi streams, for methods that take options first")
+	messages := fs.Int("messages", 10, "requests sent on each client or bidi stream, after -first")
+	percentiles := fs.String("percentiles", "50,90,95,99,99.9", "latency percentiles to report, separated by commas")
+	conns := fs.Int("conns", 1, "connections to spread the calls over")
+	seed := fs.Int64("seed", 1, "seed of the template random functions")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s run [flags] <method>\n\nThe method is a name such as Sum or /blog.BlogService/ListBlog.\n-timeout bounds each call.\n\nFlags:\n", program)
+		fs.PrintDefaults()
+	}
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return cli.Usagef("want one method, got %d arguments", fs.NArg())
+	}
+	if *conns < 1 {
+		return cli.Usagef("-conns must be at least 1, got %d", *conns)
+	}
+	if *messages < 0 {
+		return cli.Usagef("-messages must not be negative, got %d", *messages)
+	}
+	cfg.Timeout = conn.Timeout
+	var err error
+	if cfg.Percentiles, err = parsePercentiles(*percentiles); err != nil {
+		return err
+	}
+
+	method, err := bench.FindMethod(fs.Arg(0))
+	if err != nil {
+		return cli.Usagef("%v", err)
+	}
+	payload, err := bench.NewPayload(method.Input(), *data, *seed)
+	if err != nil {
+		return cli.Usagef("-data: %v", err)
+	}
+	var firstMsg func(int) (proto.Message, error)
+	if *first != "" {
+		p, err := bench.NewPayload(method.Input(), *first, *seed)
+		if err != nil {
+			return cli.Usagef("-first: %v", err)
+		}
+		firstMsg = func(n int) (proto.Message, error) { return p.Build(n, 0) }
+	}
+
+	callers := make([]*bench.Caller, *conns)
+	for i := range callers {
+		cc, err := client.Dial(conn.Target(), client.Service{Name: method.Service()}, conn.ClientOptions()...)
+		if err != nil {
+			return err
+		}
+		defer cc.Close()
+		callers[i] = &bench.Caller{
+			Conn:     cc,
+			Method:   method,
+			Request:  payload.Build,
+			First:    firstMsg,
+			Messages: *messages,
+		}
+	}
+	if err := warmUp(callers, conn.Timeout); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, err := bench.Run(ctx, cfg, func(ctx context.Context, n int) (int, error) {
+		return callers[n%len(callers)].Call(ctx, n)
+	})
+	if err != nil {
+		return cli.Usagef("%v", err)
+	}
+	report.Method = method.FullName()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return report.WriteText(os.Stdout)
+}
+
+// warmUp waits for every connection to be ready, so connection setup is not
+// counted in the latency of the first calls.
+func warmUp(callers []*bench.Caller, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, c := range callers {
+		c.Conn.Connect()
+		for state := c.Conn.GetState(); state != connectivity.Ready; state = c.Conn.GetState() {
+			if !c.Conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connecting to %s: %v", c.Conn.Target(), state)
+			}
+		}
+	}
+	return nil
+}
+
+func parsePercentiles(s string) ([]float64, error) {
+	var ps []float64
+	for _, field := range strings.Split(s, ",") {
+		p, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, cli.Usagef("invalid percentile %q", field)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func runMethods(args []string) error {
+	fs := cli.NewFlagSet(program, "methods")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tKIND\tREQUEST")
+	for _, m := range bench.Methods() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.FullName(), m.Kind(), m.Input().FullName())
+	}
+	return tw.Flush()
+}
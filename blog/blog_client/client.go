@@ -1,108 +1,336 @@
 package main
 
 import (
-	"context"
+	"flag"
 	"fmt"
-	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/status"
 	"io"
-	"log"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogclient"
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/internal/cli"
 )
 
+const program = "blog_client"
+
 func main() {
-	fmt.Println("Starting blog client...")
+	cli.Main(program, []cli.Command{
+		{Name: "create", Summary: "create a blog", Run: runCreate},
+		{Name: "get", Summary: "read a blog by ID", Run: runGet},
+		{Name: "update", Summary: "change fields of an existing blog", Run: runUpdate},
+		{Name: "delete", Summary: "delete a blog by ID", Run: runDelete},
+		{Name: "list", Summary: "list every blog", Run: runList},
+		{Name: "search", Summary: "list blogs matching an author, title or text", Run: runSearch},
+	})
+}
+
+// options are the flags shared by every subcommand.
+type options struct {
+	conn   cli.ConnFlags
+	format cli.Format
+}
+
+func newFlagSet(command string, timeout time.Duration, opts *options) *flag.FlagSet {
+	fs := cli.NewFlagSet(program, command)
+	opts.conn.Register(fs, timeout)
+	opts.format = cli.FormatTable
+	fs.Var(&opts.format, "o", "output format: table, json or yaml")
+	return fs
+}
+
+// blogFlags are the editable fields of a blog.
+type blogFlags struct {
+	author      string
+	title       string
+	content     string
+	contentFile string
+}
+
+func (b *blogFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&b.author, "author", "", "author ID")
+	fs.StringVar(&b.title, "title", "", "blog title")
+	fs.StringVar(&b.content, "content", "", "blog content")
+	fs.StringVar(&b.contentFile, "content-file", "", "read the content from a file ('-' for stdin)")
+}
+
+// apply copies the flags that were set on the command line into blog.
+func (b *blogFlags) apply(fs *flag.FlagSet, blog *blogpb.Blog) error {
+	if b.content != "" && b.contentFile != "" {
+		return cli.Usagef("-content and -content-file are mutually exclusive")
+	}
 
-	opts := grpc.WithInsecure()
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "author":
+			blog.AuthorId = b.author
+		case "title":
+			blog.Title = b.title
+		case "content":
+			blog.Content = b.content
+		case "content-file":
+			blog.Content, err = readContent(b.contentFile)
+		}
+	})
+	return err
+}
+
+func (b *blogFlags) changed(fs *flag.FlagSet) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "author", "title", "content", "content-file":
+			set = true
+		}
+	})
+	return set
+}
 
-	fmt.Println("Connecting to server...")
-	cc, err := grpc.Dial("localhost:50051", opts)
+func readContent(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
 	if err != nil {
-		log.Fatalf("could not connect: %v", err)
+		return "", fmt.Errorf("reading content: %v", err)
 	}
-	defer cc.Close()
+	return string(data), nil
+}
 
-	fmt.Println("Connected to server!")
+func dial(opts *options) (*blogclient.Client, error) {
+	return blogclient.New(opts.conn.Target(), opts.conn.ClientOptions()...)
+}
 
-	c := blogpb.NewBlogServiceClient(cc)
-	blog := createBlog(c)
-	blogID := blog.GetId()
-	readBlog(c, blogID)
-	updateBlog(c, blogID)
-	deleteBlog(c, blogID)
-	listBlog(c)
+func blogID(fs *flag.FlagSet) (string, error) {
+	if fs.NArg() != 1 {
+		return "", cli.Usagef("expected exactly one blog ID, got %d arguments", fs.NArg())
+	}
+	return fs.Arg(0), nil
 }
 
-func createBlog(c blogpb.BlogServiceClient) *blogpb.Blog {
-	res, err := c.CreateBlog(context.Background(), &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{
-		AuthorId: "22",
-		Title:    "The Chill",
-		Content:  "Too cool for school",
-	}})
+func runCreate(args []string) error {
+	var opts options
+	var fields blogFlags
+	fs := newFlagSet("create", 0, &opts)
+	fields.register(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Usagef("unexpected arguments: %v", fs.Args())
+	}
+
+	blog := &blogpb.Blog{}
+	if err := fields.apply(fs, blog); err != nil {
+		return err
+	}
+	if blog.GetAuthorId() == "" || blog.GetTitle() == "" {
+		return cli.Usagef("-author and -title are required")
+	}
 
+	c, err := dial(&opts)
 	if err != nil {
-		respErr, ok := status.FromError(err)
-		if !ok {
-			log.Printf("Request failed with code %v: %v", respErr.Code(), respErr.Err())
-		} else {
-			log.Fatalf("Unknown err: %v", err)
-		}
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: blog})
+	if err != nil {
+		return err
+	}
+
+	p := cli.NewPrinter(os.Stdout, opts.format, false)
+	p.Add(res.GetBlog())
+	return p.Flush()
+}
+
+func runGet(args []string) error {
+	var opts options
+	fs := newFlagSet("get", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	id, err := blogID(fs)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
 	}
+	defer c.Close()
 
-	blog := res.GetBlog()
-	log.Printf("Saved %v", blog)
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: id})
+	if err != nil {
+		return err
+	}
 
-	return blog
+	p := cli.NewPrinter(os.Stdout, opts.format, false)
+	p.Add(res.GetBlog())
+	return p.Flush()
 }
 
-func readBlog(c blogpb.BlogServiceClient, blogID string) {
-	fmt.Println("Reading the blog")
+func runUpdate(args []string) error {
+	var opts options
+	var fields blogFlags
+	fs := newFlagSet("update", 0, &opts)
+	fields.register(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	id, err := blogID(fs)
+	if err != nil {
+		return err
+	}
+	if !fields.changed(fs) {
+		return cli.Usagef("nothing to update: set at least one of -author, -title, -content or -content-file")
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+
+	// UpdateBlog replaces the whole blog, so start from the stored copy and
+	// only change the fields given on the command line.
+	current, err := c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: id})
+	if err != nil {
+		return err
+	}
+	blog := current.GetBlog()
+	if err := fields.apply(fs, blog); err != nil {
+		return err
+	}
 
-	readBlogReq := &blogpb.ReadBlogRequest{BlogId: blogID}
-	readBlogRes, readBlogErr := c.ReadBlog(context.Background(), readBlogReq)
-	if readBlogErr != nil {
-		fmt.Printf("Error happened while reading: %v \n", readBlogErr)
+	res, err := c.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: blog})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Blog was read: %v \n", readBlogRes)
+	p := cli.NewPrinter(os.Stdout, opts.format, false)
+	p.Add(res.GetBlog())
+	return p.Flush()
 }
 
-func updateBlog(c blogpb.BlogServiceClient, blogID string) {
-	newBlog := &blogpb.Blog{
-		Id:       blogID,
-		AuthorId: "Changed Author",
-		Title:    "My First Blog (edited)",
-		Content:  "Content of the first blog, with some awesome additions!",
+func runDelete(args []string) error {
+	var opts options
+	fs := newFlagSet("delete", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	id, err := blogID(fs)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
 	}
-	updateRes, updateErr := c.UpdateBlog(context.Background(), &blogpb.UpdateBlogRequest{Blog: newBlog})
-	if updateErr != nil {
-		fmt.Printf("Error happened while updating: %v \n", updateErr)
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: id})
+	if err != nil {
+		return err
 	}
-	fmt.Printf("Blog was updated: %v\n", updateRes)
+
+	p := cli.NewPrinter(os.Stdout, opts.format, false)
+	p.Add(res)
+	return p.Flush()
 }
 
-func deleteBlog(c blogpb.BlogServiceClient, blogID string) {
-	deleteRes, deleteErr := c.DeleteBlog(context.Background(), &blogpb.DeleteBlogRequest{BlogId: blogID})
+func runList(args []string) error {
+	var opts options
+	fs := newFlagSet("list", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Usagef("unexpected arguments: %v", fs.Args())
+	}
 
-	if deleteErr != nil {
-		fmt.Printf("Error happened while deleting: %v \n", deleteErr)
+	return listBlogs(&opts, func(*blogpb.Blog) bool { return true })
+}
+
+func runSearch(args []string) error {
+	var opts options
+	var author, title, text string
+	fs := newFlagSet("search", 0, &opts)
+	fs.StringVar(&author, "author", "", "only blogs by this author ID")
+	fs.StringVar(&title, "title", "", "only blogs whose title contains this text")
+	fs.StringVar(&text, "q", "", "only blogs whose title or content contains this text")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Usagef("unexpected arguments: %v", fs.Args())
 	}
-	fmt.Printf("Blog was deleted: %v \n", deleteRes)
+	if author == "" && title == "" && text == "" {
+		return cli.Usagef("set at least one of -author, -title or -q")
+	}
+
+	title = strings.ToLower(title)
+	text = strings.ToLower(text)
+	// The service has no search RPC, so filter the ListBlog stream here.
+	return listBlogs(&opts, func(b *blogpb.Blog) bool {
+		if author != "" && b.GetAuthorId() != author {
+			return false
+		}
+		if title != "" && !strings.Contains(strings.ToLower(b.GetTitle()), title) {
+			return false
+		}
+		if text != "" &&
+			!strings.Contains(strings.ToLower(b.GetTitle()), text) &&
+			!strings.Contains(strings.ToLower(b.GetContent()), text) {
+			return false
+		}
+		return true
+	})
 }
 
-func listBlog(c blogpb.BlogServiceClient) {
-	stream, err := c.ListBlog(context.Background(), &blogpb.ListBlogRequest{})
+func listBlogs(opts *options, keep func(*blogpb.Blog) bool) error {
+	c, err := dial(opts)
 	if err != nil {
-		log.Fatalf("error while calling ListBlog RPC: %v", err)
+		return err
 	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{})
+	if err != nil {
+		return err
+	}
+
+	p := cli.NewPrinter(os.Stdout, opts.format, true)
 	for {
 		res, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			log.Fatalf("Something happened: %v", err)
+			return err
+		}
+		if keep(res.GetBlog()) {
+			p.Add(res.GetBlog())
 		}
-		fmt.Println(res.GetBlog())
 	}
+	return p.Flush()
 }
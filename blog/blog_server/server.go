@@ -2,15 +2,22 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/pandadragoon/grpc-go-course/blog/blogserver"
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/deadline"
+	"github.com/pandadragoon/grpc-go-course/internal/grpcinfra"
+	"github.com/pandadragoon/grpc-go-course/internal/ratelimit"
+	"github.com/pandadragoon/grpc-go-course/internal/recovery"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"net"
 	"os"
@@ -18,18 +25,30 @@ import (
 	"time"
 )
 
-type server struct{}
-
-type blogItem struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	AuthorID string             `bson:"author_id"`
-	Content  string             `bson:"content"`
-	Title    string             `bson:"title"`
-}
-
-var collection *mongo.Collection
-
 func main() {
+	addr := flag.String("addr", "0.0.0.0:50051", "address to listen on")
+	mongoURI := flag.String("mongo", "mongodb://localhost:27017", "MongoDB connection URI")
+	limits := ratelimit.Config{
+		Default: ratelimit.Limit{Rate: 100, Burst: 200},
+		Methods: map[string]ratelimit.Limit{
+			// Every call scans the whole collection.
+			"/blog.BlogService/ListBlog": {Rate: 5, Burst: 10},
+		},
+		MaxStreams: 32,
+	}
+	ratelimit.RegisterFlags(flag.CommandLine, &limits)
+	deadlines := deadline.Config{
+		Limits: deadline.Limits{Default: 10 * time.Second, Max: 30 * time.Second},
+		Methods: map[string]deadline.Limits{
+			"/blog.BlogService/ListBlog": {Default: 30 * time.Second, Max: 2 * time.Minute},
+		},
+	}
+	deadline.RegisterFlags(flag.CommandLine, &deadlines)
+	compressionConfig := compression.Config{MinSize: compression.DefaultMinSize}
+	compression.RegisterFlags(flag.CommandLine, &compressionConfig)
+	flag.Parse()
+	compression.Configure(compressionConfig)
+
 	// Get file name and line number if code crashes
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	fmt.Println("Starting blog server...")
@@ -37,26 +56,44 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 	fmt.Println("Connecting to db...")
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(*mongoURI))
 	if err != nil {
 		log.Fatalf("Error connecting to database %v", err)
 	}
 
-	collection = client.Database("mydb").Collection("blog")
+	store := blogserver.NewMongoStore(client.Database("mydb").Collection("blog"))
 
-	host := "0.0.0.0"
-	port := ":50051"
-	lis, err := net.Listen("tcp", host+port)
+	lis, err := net.Listen("tcp", *addr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	fmt.Printf("Listening at %s on port %s", host, port)
+	fmt.Printf("Listening at %s\n", *addr)
 
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{
+		grpcinfra.KeepaliveEnforcement(),
+		deadline.ServerOption(deadlines),
+	}
+	limiter := ratelimit.New(limits)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor(),
+			limiter.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			recovery.StreamServerInterceptor(),
+			limiter.StreamServerInterceptor(),
+		),
+	)
 
 	s := grpc.NewServer(opts...)
-	blogpb.RegisterBlogServiceServer(s, &server{})
+	blogpb.RegisterBlogServiceServer(s, blogserver.New(store))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	go watchDatabase(client, healthServer)
 
 	go func() {
 		if err := s.Serve(lis); err != nil {
@@ -68,173 +105,29 @@ func main() {
 	signal.Notify(ch, os.Interrupt)
 	<-ch
 	fmt.Println("\nStopping the server")
+	healthServer.Shutdown()
 	s.Stop()
 	fmt.Println("Closing the listener")
 	lis.Close()
 	fmt.Println("Exiting program")
 }
 
-func (*server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
-	blog := req.GetBlog()
-	data := blogItem{
-		AuthorID: blog.GetAuthorId(),
-		Title:    blog.GetTitle(),
-		Content:  blog.GetContent(),
-	}
-
-	result, err := collection.InsertOne(context.Background(), data)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error %v", err))
-	}
-
-	oid, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Cannot parse oid"))
-	}
-
-	return &blogpb.CreateBlogResponse{
-		Blog: &blogpb.Blog{
-			Id:       oid.Hex(),
-			AuthorId: blog.GetAuthorId(),
-			Title:    blog.GetTitle(),
-			Content:  blog.GetContent(),
-		},
-	}, nil
-}
-
-func (*server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
-	fmt.Println("Update blog request")
-	blog := req.GetBlog()
-	oid, err := primitive.ObjectIDFromHex(blog.GetId())
-	if err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Cannot parse ID"),
-		)
-	}
-
-	// create an empty struct
-	data := &blogItem{}
-	filter := bson.M{"_id": oid}
-
-	res := collection.FindOne(context.Background(), filter)
-	if err := res.Decode(data); err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog with specified ID: %v", err),
-		)
-	}
-
-	// we update our internal struct
-	data.AuthorID = blog.GetAuthorId()
-	data.Content = blog.GetContent()
-	data.Title = blog.GetTitle()
-
-	_, updateErr := collection.ReplaceOne(context.Background(), filter, data)
-	if updateErr != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Cannot update object in MongoDB: %v", updateErr),
-		)
-	}
-
-	return &blogpb.UpdateBlogResponse{
-		Blog: dataToBlogPb(data),
-	}, nil
+// watchDatabase reports the blog service as serving only while MongoDB
+// answers pings, so load balancing clients skip replicas that lost their
+// database.
+func watchDatabase(client *mongo.Client, healthServer *health.Server) {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err := client.Ping(ctx, readpref.Primary())
+		cancel()
 
-}
-
-func (*server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
-	fmt.Println("Delete blog request")
-	oid, err := primitive.ObjectIDFromHex(req.GetBlogId())
-	if err != nil {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Cannot parse ID"),
-		)
-	}
-
-	filter := bson.M{"_id": oid}
-
-	res, err := collection.DeleteOne(context.Background(), filter)
-
-	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Cannot delete object in MongoDB: %v", err),
-		)
-	}
-
-	if res.DeletedCount == 0 {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog in MongoDB: %v", err),
-		)
-	}
-
-	return &blogpb.DeleteBlogResponse{BlogId: req.GetBlogId()}, nil
-}
-
-func (*server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
-	fmt.Println("List blog request")
-
-	cur, err := collection.Find(context.Background(), primitive.D{{}})
-	if err != nil {
-		return status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Unknown internal error: %v", err),
-		)
-	}
-	defer cur.Close(context.Background())
-	for cur.Next(context.Background()) {
-		data := &blogItem{}
-		err := cur.Decode(data)
+		servingStatus := healthpb.HealthCheckResponse_SERVING
 		if err != nil {
-			return status.Errorf(
-				codes.Internal,
-				fmt.Sprintf("Error while decoding data from MongoDB: %v", err),
-			)
-
+			log.Printf("Database ping failed: %v", err)
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
 		}
-		stream.Send(&blogpb.ListBlogResponse{Blog: dataToBlogPb(data)})
-	}
-	if err := cur.Err(); err != nil {
-		return status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Unknown internal error: %v", err),
-		)
-	}
-	return nil
-}
-
-func (*server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
-	blogId := req.GetBlogId()
-	oid, err := primitive.ObjectIDFromHex(blogId)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Cannot parse ID %v", blogId))
-	}
-
-	data := &blogItem{}
-	filter := bson.M{"_id": oid}
-
-	result := collection.FindOne(context.Background(), filter)
-	if err := result.Decode(data); err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Cannot find blog with specified ID: %v", err),
-		)
-	}
-
-	return &blogpb.ReadBlogResponse{
-		Blog: dataToBlogPb(data),
-	}, nil
-}
+		healthServer.SetServingStatus("blog.BlogService", servingStatus)
 
-func dataToBlogPb(data *blogItem) *blogpb.Blog {
-	return &blogpb.Blog{
-		Id:       data.ID.Hex(),
-		AuthorId: data.AuthorID,
-		Content:  data.Content,
-		Title:    data.Title,
+		time.Sleep(10 * time.Second)
 	}
 }
@@ -0,0 +1,50 @@
+// Package blogclient is the client library for BlogService.
+package blogclient
+
+import (
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/client"
+	"google.golang.org/grpc"
+)
+
+const service = "blog.BlogService"
+
+// MethodConfigs returns the default method configuration: ReadBlog is
+// idempotent and retried, and every unary call gets a 10s deadline.
+// CreateBlog, UpdateBlog and DeleteBlog are not retried since a lost
+// response does not mean the write did not happen.
+func MethodConfigs() []client.MethodConfig {
+	retry := client.DefaultRetryPolicy
+	return []client.MethodConfig{
+		{Service: service, Method: "ReadBlog", Timeout: 10 * time.Second, Retry: &retry},
+		{Service: service, Method: "CreateBlog", Timeout: 10 * time.Second},
+		{Service: service, Method: "UpdateBlog", Timeout: 10 * time.Second},
+		{Service: service, Method: "DeleteBlog", Timeout: 10 * time.Second},
+	}
+}
+
+// Client is a BlogServiceClient that owns its connection.
+type Client struct {
+	blogpb.BlogServiceClient
+	conn *grpc.ClientConn
+}
+
+// New dials target with MethodConfigs and opts. target is anything
+// client.Dial accepts, including client.Target of several replicas.
+func New(target string, opts ...client.Option) (*Client, error) {
+	conn, err := client.Dial(target, client.Service{Name: service, Methods: MethodConfigs()}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		BlogServiceClient: blogpb.NewBlogServiceClient(conn),
+		conn:              conn,
+	}, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,7 @@
+// Package blogmock provides mocks of the blog service's client and stream
+// interfaces, for testing code that uses blogpb without a server. Client
+// mocks record their calls and answer with programmable functions; stream
+// mocks replay programmed messages and record the ones sent.
+package blogmock
+
+//go:generate go run ../../internal/cmd/genmock -proto blog/blogpb/blog.proto -package blogmock -o mock.go
@@ -0,0 +1,107 @@
+package blogserver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryStore is a Store that keeps blogs in memory, for tests and for
+// running without a database.
+type MemoryStore struct {
+	mu    sync.Mutex
+	blogs map[string]*blogpb.Blog
+	// order holds the IDs in insertion order.
+	order []string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blogs: make(map[string]*blogpb.Blog)}
+}
+
+func (m *MemoryStore) Create(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error) {
+	stored := proto.Clone(blog).(*blogpb.Blog)
+	stored.Id = primitive.NewObjectID().Hex()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blogs[stored.Id] = stored
+	m.order = append(m.order, stored.Id)
+	return proto.Clone(stored).(*blogpb.Blog), nil
+}
+
+func (m *MemoryStore) Read(ctx context.Context, id string) (*blogpb.Blog, error) {
+	if !validID(id) {
+		return nil, ErrInvalidID
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	blog, ok := m.blogs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(blog).(*blogpb.Blog), nil
+}
+
+func (m *MemoryStore) Update(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error) {
+	if !validID(blog.GetId()) {
+		return nil, ErrInvalidID
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.blogs[blog.GetId()]; !ok {
+		return nil, ErrNotFound
+	}
+	stored := proto.Clone(blog).(*blogpb.Blog)
+	m.blogs[stored.Id] = stored
+	return proto.Clone(stored).(*blogpb.Blog), nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	if !validID(id) {
+		return ErrInvalidID
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.blogs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.blogs, id)
+	for i, other := range m.order {
+		if other == id {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// List works on a snapshot, so fn may take its time without holding up
+// other calls.
+func (m *MemoryStore) List(ctx context.Context, fn func(*blogpb.Blog) error) error {
+	m.mu.Lock()
+	blogs := make([]*blogpb.Blog, len(m.order))
+	for i, id := range m.order {
+		blogs[i] = proto.Clone(m.blogs[id]).(*blogpb.Blog)
+	}
+	m.mu.Unlock()
+
+	for _, blog := range blogs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(blog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validID(id string) bool {
+	_, err := primitive.ObjectIDFromHex(id)
+	return err == nil
+}
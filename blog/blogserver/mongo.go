@@ -0,0 +1,131 @@
+package blogserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoStore is a Store backed by a MongoDB collection.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore returns a store keeping blogs in collection.
+func NewMongoStore(collection *mongo.Collection) *MongoStore {
+	return &MongoStore{collection: collection}
+}
+
+type blogItem struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	AuthorID string             `bson:"author_id"`
+	Content  string             `bson:"content"`
+	Title    string             `bson:"title"`
+}
+
+func (m *MongoStore) Create(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error) {
+	data := blogItem{
+		AuthorID: blog.GetAuthorId(),
+		Title:    blog.GetTitle(),
+		Content:  blog.GetContent(),
+	}
+
+	result, err := m.collection.InsertOne(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse inserted ID %v", result.InsertedID)
+	}
+	data.ID = oid
+	return dataToBlogPb(&data), nil
+}
+
+func (m *MongoStore) Read(ctx context.Context, id string) (*blogpb.Blog, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	data := &blogItem{}
+	if err := m.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(data); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return dataToBlogPb(data), nil
+}
+
+func (m *MongoStore) Update(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error) {
+	oid, err := primitive.ObjectIDFromHex(blog.GetId())
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	data := &blogItem{
+		ID:       oid,
+		AuthorID: blog.GetAuthorId(),
+		Content:  blog.GetContent(),
+		Title:    blog.GetTitle(),
+	}
+	res, err := m.collection.ReplaceOne(ctx, bson.M{"_id": oid}, data)
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, ErrNotFound
+	}
+	return dataToBlogPb(data), nil
+}
+
+func (m *MongoStore) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	res, err := m.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (m *MongoStore) List(ctx context.Context, fn func(*blogpb.Blog) error) error {
+	cur, err := m.collection.Find(ctx, primitive.D{{}})
+	if err != nil {
+		return err
+	}
+	// Close even when ctx is done, to release the server-side cursor.
+	defer cur.Close(context.Background())
+	for cur.Next(ctx) {
+		data := &blogItem{}
+		if err := cur.Decode(data); err != nil {
+			return fmt.Errorf("decoding blog: %w", err)
+		}
+		if err := fn(dataToBlogPb(data)); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
+
+func dataToBlogPb(data *blogItem) *blogpb.Blog {
+	return &blogpb.Blog{
+		Id:       data.ID.Hex(),
+		AuthorId: data.AuthorID,
+		Content:  data.Content,
+		Title:    data.Title,
+	}
+}
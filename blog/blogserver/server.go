@@ -0,0 +1,132 @@
+// Package blogserver implements the blog service on top of a Store.
+// Registering it is up to the caller, along with the interceptors and
+// options of the server.
+package blogserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc/status"
+)
+
+// domain is the ErrorInfo domain of blog errors.
+const domain rpcerr.Domain = "blog.BlogService"
+
+// Reasons of blog errors.
+const (
+	ReasonInvalidID    = "INVALID_BLOG_ID"
+	ReasonBlogNotFound = "BLOG_NOT_FOUND"
+)
+
+// Server implements blogpb.BlogServiceServer.
+type Server struct {
+	// Methods added to the proto answer Unimplemented until they are
+	// implemented here.
+	blogpb.UnimplementedBlogServiceServer
+
+	store Store
+}
+
+// New returns a blog service keeping its blogs in store.
+func New(store Store) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
+	blog, err := s.store.Create(ctx, req.GetBlog())
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+
+	return &blogpb.CreateBlogResponse{Blog: blog}, nil
+}
+
+func (s *Server) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogRequest) (*blogpb.UpdateBlogResponse, error) {
+	requestid.Logf(ctx, "Update blog request")
+	blog, err := s.store.Update(ctx, req.GetBlog())
+	switch {
+	case errors.Is(err, ErrInvalidID):
+		return nil, invalidID("blog.id", req.GetBlog().GetId())
+	case errors.Is(err, ErrNotFound):
+		return nil, notFound(req.GetBlog().GetId())
+	case err != nil:
+		return nil, storeError(ctx, err)
+	}
+
+	return &blogpb.UpdateBlogResponse{Blog: blog}, nil
+}
+
+func (s *Server) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogRequest) (*blogpb.DeleteBlogResponse, error) {
+	requestid.Logf(ctx, "Delete blog request")
+	err := s.store.Delete(ctx, req.GetBlogId())
+	switch {
+	case errors.Is(err, ErrInvalidID):
+		return nil, invalidID("blog_id", req.GetBlogId())
+	case errors.Is(err, ErrNotFound):
+		return nil, notFound(req.GetBlogId())
+	case err != nil:
+		return nil, storeError(ctx, err)
+	}
+
+	return &blogpb.DeleteBlogResponse{BlogId: req.GetBlogId()}, nil
+}
+
+func (s *Server) ListBlog(req *blogpb.ListBlogRequest, stream blogpb.BlogService_ListBlogServer) error {
+	ctx := stream.Context()
+	requestid.Logf(ctx, "List blog request")
+
+	var sendErr error
+	err := s.store.List(ctx, func(blog *blogpb.Blog) error {
+		sendErr = stream.Send(&blogpb.ListBlogResponse{Blog: blog})
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return storeError(ctx, err)
+	}
+	return nil
+}
+
+func (s *Server) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
+	blogId := req.GetBlogId()
+	blog, err := s.store.Read(ctx, blogId)
+	switch {
+	case errors.Is(err, ErrInvalidID):
+		return nil, invalidID("blog_id", blogId)
+	case errors.Is(err, ErrNotFound):
+		return nil, notFound(blogId)
+	case err != nil:
+		return nil, storeError(ctx, err)
+	}
+
+	return &blogpb.ReadBlogResponse{Blog: blog}, nil
+}
+
+func invalidID(field, id string) error {
+	return domain.InvalidField(ReasonInvalidID, field, fmt.Sprintf("Cannot parse ID %q", id))
+}
+
+func notFound(id string) error {
+	return domain.NotFound(ReasonBlogNotFound, "blog.Blog", id, "Cannot find blog with specified ID: "+id)
+}
+
+// storeError reports a failed store call. When the call's deadline passed
+// or it was cancelled, that is the error, whatever the store made of it;
+// otherwise it is an internal error, which the client sees without the
+// store's error.
+func storeError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Err()
+	}
+	return domain.Internal(ctx, err)
+}
@@ -0,0 +1,413 @@
+package blogserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/blog/blogserver"
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// missingID is a valid ID that no blog has.
+const missingID = "5f8f8c44b54764421b7156c9"
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func newBlog(author, title string) *blogpb.Blog {
+	return &blogpb.Blog{AuthorId: author, Title: title, Content: "Content of " + title}
+}
+
+func TestCreateBlog(t *testing.T) {
+	c, store := servertest.Blog(t)
+	ctx := testContext(t)
+
+	blog := newBlog("ada", "Notes")
+	blog.Id = "ignored"
+	res, err := c.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: blog})
+	if err != nil {
+		t.Fatalf("CreateBlog: %v", err)
+	}
+	got := res.GetBlog()
+	if got.GetId() == "" || got.GetId() == "ignored" {
+		t.Errorf("CreateBlog: got ID %q, want a new one", got.GetId())
+	}
+	want := proto.Clone(blog).(*blogpb.Blog)
+	want.Id = got.GetId()
+	if !proto.Equal(got, want) {
+		t.Errorf("CreateBlog: got %v, want %v", got, want)
+	}
+
+	stored, err := store.Read(ctx, got.GetId())
+	if err != nil || !proto.Equal(stored, want) {
+		t.Errorf("stored blog: got (%v, %v), want %v", stored, err, want)
+	}
+
+	other, err := c.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: blog})
+	if err != nil {
+		t.Fatalf("CreateBlog: %v", err)
+	}
+	if other.GetBlog().GetId() == got.GetId() {
+		t.Errorf("CreateBlog: two blogs got ID %q", got.GetId())
+	}
+}
+
+func TestReadUpdateDeleteBlog(t *testing.T) {
+	c, store := servertest.Blog(t)
+	existing, err := store.Create(testContext(t), newBlog("ada", "Notes"))
+	if err != nil {
+		t.Fatalf("creating blog: %v", err)
+	}
+	updated := &blogpb.Blog{Id: existing.GetId(), AuthorId: "bob", Title: "Edited", Content: "New content"}
+
+	tests := []struct {
+		name     string
+		call     func(context.Context) (proto.Message, error)
+		want     proto.Message
+		wantCode codes.Code
+	}{
+		{
+			name: "read",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: existing.GetId()})
+			},
+			want: &blogpb.ReadBlogResponse{Blog: existing},
+		},
+		{
+			name: "read missing",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: missingID})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "read invalid ID",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: "not-an-id"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "update missing",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{Id: missingID, Title: "Edited"}})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "update invalid ID",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: &blogpb.Blog{Title: "Edited"}})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "update",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.UpdateBlog(ctx, &blogpb.UpdateBlogRequest{Blog: updated})
+			},
+			want: &blogpb.UpdateBlogResponse{Blog: updated},
+		},
+		{
+			name: "read updated",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: existing.GetId()})
+			},
+			want: &blogpb.ReadBlogResponse{Blog: updated},
+		},
+		{
+			name: "delete invalid ID",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: "not-an-id"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "delete",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: existing.GetId()})
+			},
+			want: &blogpb.DeleteBlogResponse{BlogId: existing.GetId()},
+		},
+		{
+			name: "delete again",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DeleteBlog(ctx, &blogpb.DeleteBlogRequest{BlogId: existing.GetId()})
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "read deleted",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: existing.GetId()})
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+	// The cases run in order, each seeing the effects of the ones before.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call(testContext(t))
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("got %v, want %v", err, tt.wantCode)
+				}
+				checkDetails(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// checkDetails checks the error details of NOT_FOUND and INVALID_ARGUMENT
+// errors.
+func checkDetails(t *testing.T, err error) {
+	t.Helper()
+	d := rpcerr.Decode(err)
+	switch d.Code {
+	case codes.NotFound:
+		if d.Info.GetReason() != blogserver.ReasonBlogNotFound || d.Resource.GetResourceType() != "blog.Blog" || d.Resource.GetResourceName() == "" {
+			t.Errorf("%v: got ErrorInfo %v and ResourceInfo %v, want %s of a blog.Blog", err, d.Info, d.Resource, blogserver.ReasonBlogNotFound)
+		}
+	case codes.InvalidArgument:
+		if d.Info.GetReason() != blogserver.ReasonInvalidID || len(d.Request.GetFieldViolations()) != 1 {
+			t.Errorf("%v: got ErrorInfo %v and BadRequest %v, want %s with a field violation", err, d.Info, d.Request, blogserver.ReasonInvalidID)
+		}
+	}
+}
+
+// failingStore fails every call the way a database that went away does.
+type failingStore struct{}
+
+var errDatabase = errors.New("server selection error: mongodb://db.internal:27017 unreachable")
+
+func (failingStore) Create(context.Context, *blogpb.Blog) (*blogpb.Blog, error) {
+	return nil, errDatabase
+}
+func (failingStore) Read(context.Context, string) (*blogpb.Blog, error) { return nil, errDatabase }
+func (failingStore) Update(context.Context, *blogpb.Blog) (*blogpb.Blog, error) {
+	return nil, errDatabase
+}
+func (failingStore) Delete(context.Context, string) error { return errDatabase }
+func (failingStore) List(context.Context, func(*blogpb.Blog) error) error {
+	return errDatabase
+}
+
+func TestStoreErrors(t *testing.T) {
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		blogpb.RegisterBlogServiceServer(s, blogserver.New(failingStore{}))
+	})
+	c := blogpb.NewBlogServiceClient(conn)
+	ctx := testContext(t)
+
+	calls := map[string]func() error{
+		"CreateBlog": func() error {
+			_, err := c.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: newBlog("ada", "Notes")})
+			return err
+		},
+		"ReadBlog": func() error {
+			_, err := c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: missingID})
+			return err
+		},
+		"ListBlog": func() error {
+			stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if status.Code(err) != codes.Internal {
+			t.Errorf("%s: got %v, want Internal", name, err)
+			continue
+		}
+		if msg := status.Convert(err).Message(); strings.Contains(msg, "mongodb") {
+			t.Errorf("%s: message %q exposes the database error", name, msg)
+		}
+		d := rpcerr.Decode(err)
+		if d.Info.GetReason() != rpcerr.ReasonInternal || d.Info.GetMetadata()["incident"] == "" {
+			t.Errorf("%s: got ErrorInfo %v, want an INTERNAL incident", name, d.Info)
+		}
+	}
+}
+
+func TestListBlog(t *testing.T) {
+	tests := []struct {
+		name   string
+		titles []string
+	}{
+		{"empty", nil},
+		{"one", []string{"First"}},
+		{"several", []string{"First", "Second", "Third"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, store := servertest.Blog(t)
+			ctx := testContext(t)
+			var want []*blogpb.Blog
+			for _, title := range tt.titles {
+				blog, err := store.Create(ctx, newBlog("ada", title))
+				if err != nil {
+					t.Fatalf("creating blog: %v", err)
+				}
+				want = append(want, blog)
+			}
+
+			stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{})
+			if err != nil {
+				t.Fatalf("ListBlog: %v", err)
+			}
+			var got []*blogpb.Blog
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("ListBlog Recv: %v", err)
+				}
+				got = append(got, res.GetBlog())
+			}
+			if len(got) != len(want) {
+				t.Fatalf("ListBlog: got %v, want %v", got, want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], want[i]) {
+					t.Errorf("blog %d: got %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListBlogCancel(t *testing.T) {
+	c, store := servertest.Blog(t)
+	ctx, cancel := context.WithCancel(testContext(t))
+	for i := 0; i < 1000; i++ {
+		if _, err := store.Create(ctx, newBlog("ada", "Post")); err != nil {
+			t.Fatalf("creating blog: %v", err)
+		}
+	}
+
+	stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{})
+	if err != nil {
+		t.Fatalf("ListBlog: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("ListBlog Recv: %v", err)
+	}
+	cancel()
+	for {
+		_, err := stream.Recv()
+		if err == nil {
+			continue
+		}
+		// The server may have sent everything before it noticed.
+		if err != io.EOF && status.Code(err) != codes.Canceled {
+			t.Fatalf("ListBlog after cancel: got %v, want Canceled", err)
+		}
+		break
+	}
+
+	// The server still answers.
+	if _, err := c.ReadBlog(testContext(t), &blogpb.ReadBlogRequest{BlogId: missingID}); status.Code(err) != codes.NotFound {
+		t.Errorf("ReadBlog after a cancelled ListBlog: got %v, want NotFound", err)
+	}
+}
+
+// words make up the content of benchmark blogs, which compresses about as
+// well as prose.
+var words = strings.Fields(`the of and to in is that it was for on are as with his they at be this
+	from have or by one had not but what all were when we there can an your which their said if do will
+	each about how up out them then she many some so these would other into has more her two like him see
+	time could no make than first been its who now people my made over did down only way find use may
+	water long little very after words called just where most know get through back much before go good`)
+
+func benchContent(rng *rand.Rand, size int) string {
+	var b strings.Builder
+	for b.Len() < size {
+		b.WriteString(words[rng.Intn(len(words))])
+		b.WriteByte(' ')
+	}
+	return b.String()[:size]
+}
+
+// BenchmarkListBlog lists 100 blogs with each compressor, reporting the
+// bytes taken on the wire per call next to the time. Blogs below the
+// minimum size are stored rather than compressed, which min-size=0 turns
+// off for comparison.
+func BenchmarkListBlog(b *testing.B) {
+	for _, size := range []int{256, 4096} {
+		for _, name := range compression.Names {
+			minSizes := []int{compression.DefaultMinSize}
+			if name != compression.Identity {
+				minSizes = append(minSizes, 0)
+			}
+			for _, minSize := range minSizes {
+				b.Run(fmt.Sprintf("content=%d/%s/min-size=%d", size, name, minSize), func(b *testing.B) {
+					compression.Configure(compression.Config{MinSize: minSize})
+					defer compression.Configure(compression.Config{MinSize: compression.DefaultMinSize})
+					benchmarkListBlog(b, size, name)
+				})
+			}
+		}
+	}
+}
+
+func benchmarkListBlog(b *testing.B, size int, compressor string) {
+	payloads := new(servertest.Payloads)
+	c, store := servertest.Blog(b, servertest.WithDialOptions(grpc.WithStatsHandler(payloads)))
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if _, err := store.Create(ctx, &blogpb.Blog{AuthorId: "ada", Title: "Post", Content: benchContent(rng, size)}); err != nil {
+			b.Fatalf("creating blog: %v", err)
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{}, client.UseCompression(compressor))
+		if err != nil {
+			b.Fatalf("ListBlog: %v", err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatalf("ListBlog Recv: %v", err)
+			}
+		}
+	}
+	b.StopTimer()
+
+	_, length, onWire := payloads.Received()
+	b.ReportMetric(float64(onWire)/float64(b.N), "wire-B/op")
+	b.ReportMetric(float64(onWire)/float64(length), "wire/size")
+}
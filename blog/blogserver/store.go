@@ -0,0 +1,31 @@
+package blogserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+)
+
+// Errors returned by stores.
+var (
+	ErrNotFound  = errors.New("blog not found")
+	ErrInvalidID = errors.New("invalid blog ID")
+)
+
+// Store keeps the blogs of the service. IDs are MongoDB object IDs in hex,
+// whatever the store, so clients see the same IDs from all of them.
+type Store interface {
+	// Create stores a new blog, ignoring its ID, and returns it with the ID
+	// it was given.
+	Create(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error)
+	// Read returns the blog with the given ID.
+	Read(ctx context.Context, id string) (*blogpb.Blog, error)
+	// Update replaces the blog with the ID of blog.
+	Update(ctx context.Context, blog *blogpb.Blog) (*blogpb.Blog, error)
+	// Delete removes the blog with the given ID.
+	Delete(ctx context.Context, id string) error
+	// List calls fn for every blog in insertion order, stopping at the
+	// first error, which it returns.
+	List(ctx context.Context, fn func(*blogpb.Blog) error) error
+}
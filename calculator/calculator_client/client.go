@@ -1,199 +1,544 @@
 package main
 
 import (
-	"context"
+	"bufio"
+	"flag"
 	"fmt"
-	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/status"
 	"io"
-	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorclient"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/internal/cli"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+const program = "calculator_client"
+
 func main() {
-	fmt.Println("Hello I'm a Calculator Client")
+	cli.Main(program, []cli.Command{
+		{Name: "sum", Summary: "add two numbers", Run: runSum},
+		{Name: "primes", Summary: "decompose a number into prime factors", Run: runPrimes},
+		{Name: "average", Summary: "average of numbers from arguments or stdin", Run: runAverage},
+		{Name: "max", Summary: "running maximum of numbers from arguments or stdin", Run: runMax},
+		{Name: "sqrt", Summary: "square root of a number", Run: runSqrt},
+		{Name: "stats", Summary: "statistics of numbers from arguments or stdin", Run: runStats},
+		{Name: "window", Summary: "aggregate numbers from arguments or stdin over windows", Run: runWindow},
+	})
+}
 
-	cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+// options are the flags shared by every subcommand.
+type options struct {
+	conn cli.ConnFlags
+	json bool
+}
+
+func newFlagSet(command string, timeout time.Duration, opts *options) *flag.FlagSet {
+	fs := cli.NewFlagSet(program, command)
+	opts.conn.Register(fs, timeout)
+	fs.BoolVar(&opts.json, "json", false, "print responses as JSON, one object per line")
+	return fs
+}
 
+func dial(opts *options) (*calculatorclient.Client, error) {
+	return calculatorclient.New(opts.conn.Target(), opts.conn.ClientOptions()...)
+}
+
+// print writes res as a JSON line, or text in plain mode.
+func (o *options) print(res proto.Message, text string) error {
+	if o.json {
+		return cli.WriteJSONLine(os.Stdout, res)
+	}
+	_, err := fmt.Println(text)
+	return err
+}
+
+func parseInt32(s string) (int32, error) {
+	n, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
-		log.Fatalf("Could not connnect: %v", err)
+		return 0, cli.Usagef("invalid number %q: want a 32-bit integer", s)
 	}
+	return int32(n), nil
+}
+
+// numbers calls fn for each number given as an argument or, when there are no
+// arguments (or the only one is "-"), for each whitespace separated number
+// read from stdin as soon as it is read.
+func numbers(fs *flag.FlagSet, fn func(int32) error) error {
+	return words(fs, func(word string) error {
+		n, err := parseInt32(word)
+		if err != nil {
+			return err
+		}
+		return fn(n)
+	})
+}
 
-	defer cc.Close()
+// words is numbers before parsing.
+func words(fs *flag.FlagSet, fn func(string) error) error {
+	args := fs.Args()
+	if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-	c := calculatorpb.NewCalculatorServiceClient(cc)
-	//doUnary(c)
-	//doServerStreaming(c)
-	//doClientStreaming(c)
-	//doBiDiStreaming(c)
-	doErrorUnary(c)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stdin: %v", err)
+	}
+	return nil
 }
 
-func doUnary(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a Unary RPC")
-	req := &calculatorpb.SumRequest{
-		FirstNumber:  3,
-		SecondNumber: 4,
+func runSum(args []string) error {
+	var opts options
+	fs := newFlagSet("sum", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
 	}
-	res, err := c.Sum(context.Background(), req)
+	if fs.NArg() != 2 {
+		return cli.Usagef("expected two numbers, got %d arguments", fs.NArg())
+	}
+	first, err := parseInt32(fs.Arg(0))
 	if err != nil {
-		log.Fatalf("Error while calling Greet RPC: %v", err)
+		return err
 	}
+	second, err := parseInt32(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
 
-	log.Printf("Response from Greet: %v", res.SumResult)
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: first, SecondNumber: second})
+	if err != nil {
+		return err
+	}
+	return opts.print(res, strconv.Itoa(int(res.GetSumResult())))
 }
 
-func doServerStreaming(c calculatorpb.CalculatorServiceClient) {
-	fmt.Println("Starting to do a Server Streaming RPC")
-	var number int64 = 2342424534
-	req := calculatorpb.PrimeNumberDecompositionRequest{
-		Number: number,
+func runPrimes(args []string) error {
+	var opts options
+	fs := newFlagSet("primes", 30*time.Second, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return cli.Usagef("expected one number, got %d arguments", fs.NArg())
+	}
+	req := &calculatorpb.PrimeNumberDecompositionRequest{}
+	if number, err := strconv.ParseInt(fs.Arg(0), 10, 64); err == nil {
+		req.Number = number
+	} else if _, ok := new(big.Int).SetString(fs.Arg(0), 10); ok {
+		req.BigNumber = fs.Arg(0)
+	} else {
+		return cli.Usagef("invalid number %q: want a decimal integer", fs.Arg(0))
 	}
 
-	client, err := c.PrimeNumberDecomposition(context.Background(), &req)
+	c, err := dial(&opts)
 	if err != nil {
-		log.Fatalf("Error calling PrimeNumberDecomposition server %v", err)
+		return err
 	}
+	defer c.Close()
 
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.PrimeNumberDecomposition(ctx, req)
+	if err != nil {
+		return err
+	}
 	for {
-		res, err := client.Recv()
+		res, err := stream.Recv()
 		if err == io.EOF {
-			fmt.Printf("Done decomposing")
-			break
+			return nil
 		}
 		if err != nil {
-			log.Fatalf("Error getting sever stream %v", err)
+			return err
+		}
+		text := res.GetBigPrimeFactor()
+		if res.GetMultiplicity() > 1 {
+			text += "^" + strconv.Itoa(int(res.GetMultiplicity()))
 		}
+		if err := opts.print(res, text); err != nil {
+			return err
+		}
+	}
+}
+
+func runAverage(args []string) error {
+	var opts options
+	fs := newFlagSet("average", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
 
-		fmt.Printf("Prime factor %d of %d\n", res.GetPrimeFactor(), number)
+	c, err := dial(&opts)
+	if err != nil {
+		return err
 	}
+	defer c.Close()
 
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.ComputeAverage(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = numbers(fs, func(n int32) error {
+		return stream.Send(&calculatorpb.ComputeAverageRequest{Number: n})
+	})
+	if err == io.EOF {
+		// The server ended the call early; CloseAndRecv reports why.
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return opts.print(res, strconv.FormatFloat(res.GetAverage(), 'f', -1, 64))
 }
 
-func doClientStreaming(c calculatorpb.CalculatorServiceClient) {
-	numbers := []int32{
-		20,
-		30,
-		22,
-		26,
-		37,
-		18,
-		25,
-		25,
-		26,
-		34,
-		21,
+func runMax(args []string) error {
+	var opts options
+	fs := newFlagSet("max", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
 	}
 
-	stream, err := c.ComputeAverage(context.Background())
+	c, err := dial(&opts)
 	if err != nil {
-		log.Fatalf("Error getting stream from server %v", err)
+		return err
 	}
+	defer c.Close()
 
-	for _, num := range numbers {
-		req := calculatorpb.ComputeAverageRequest{
-			Number: num,
-		}
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.FindMaximum(ctx)
+	if err != nil {
+		return err
+	}
 
-		fmt.Printf("Sending requests %d \n", req.GetNumber())
-		err := stream.Send(&req)
-		if err != nil {
-			log.Fatalf("Error sending request on stream %v", err)
+	// Print each new maximum as it comes back while numbers are still being
+	// read and sent.
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if err := opts.print(res, strconv.Itoa(int(res.GetMaximum()))); err != nil {
+				recvErr <- err
+				return
+			}
 		}
+	}()
 
-		time.Sleep(500 * time.Millisecond)
+	sendErr := numbers(fs, func(n int32) error {
+		return stream.Send(&calculatorpb.FindMaximumRequest{Number: n})
+	})
+	if sendErr != nil && sendErr != io.EOF {
+		cancel()
+		<-recvErr
+		return sendErr
 	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return <-recvErr
+}
 
-	res, err := stream.CloseAndRecv()
+func runSqrt(args []string) error {
+	var opts options
+	fs := newFlagSet("sqrt", 0, &opts)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return cli.Usagef("expected one number, got %d arguments", fs.NArg())
+	}
+	number, err := parseInt32(fs.Arg(0))
 	if err != nil {
-		log.Fatalf("Error closing stream %v", err)
+		return err
 	}
 
-	fmt.Printf("Average of %v was %f \n", numbers, res.GetAverage())
-}
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
 
-func doBiDiStreaming(c calculatorpb.CalculatorServiceClient) {
-	stream, err := c.FindMaximum(context.Background())
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.SquareRoot(ctx, &calculatorpb.SquareRootRequest{Number: number})
 	if err != nil {
-		log.Fatalf("Error connections to server stream %v", err)
+		return err
 	}
-	maximum := int32(0)
+	return opts.print(res, strconv.FormatFloat(res.GetNumberRoot(), 'f', -1, 64))
+}
 
-	waitc := make(chan struct{})
+// percentiles is a comma separated list of percentiles flag.
+type percentiles []float64
 
-	numbers := []int32{
-		20,
-		30,
-		22,
-		26,
-		37,
-		18,
-		25,
-		25,
-		26,
-		34,
-		21,
+func (p *percentiles) String() string {
+	var parts []string
+	for _, v := range *p {
+		parts = append(parts, strconv.FormatFloat(v, 'f', -1, 64))
 	}
+	return strings.Join(parts, ",")
+}
 
-	go func() {
-		for _, num := range numbers {
-			req := calculatorpb.FindMaximumRequest{
-				Number: num,
-			}
+func (p *percentiles) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || v < 0 || v > 100 {
+			return fmt.Errorf("invalid percentile %q: want a number between 0 and 100", part)
+		}
+		*p = append(*p, v)
+	}
+	return nil
+}
 
-			err := stream.Send(&req)
-			if err == io.EOF {
-				log.Printf("Done streaming to server stream")
-				break
-			}
+func runStats(args []string) error {
+	var opts options
+	var ps percentiles
+	fs := newFlagSet("stats", 0, &opts)
+	fs.Var(&ps, "p", "comma separated `percentiles` to report besides the median, e.g. 90,99")
+	every := fs.Int("every", 0, "print running statistics after every `n` numbers instead of once at the end")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if *every < 0 {
+		return cli.Usagef("-every must not be negative")
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+
+	first := &calculatorpb.ComputeStatisticsRequest{
+		Request: &calculatorpb.ComputeStatisticsRequest_Options{
+			Options: &calculatorpb.StatisticsOptions{Percentiles: ps, EmitEvery: int32(*every)},
+		},
+	}
+	send := func(stream interface {
+		Send(*calculatorpb.ComputeStatisticsRequest) error
+	}) error {
+		if err := stream.Send(first); err != nil {
+			return err
+		}
+		return words(fs, func(word string) error {
+			n, err := strconv.ParseFloat(word, 64)
 			if err != nil {
-				log.Fatalf("Error sending request to server stream %v", err)
+				return cli.Usagef("invalid number %q", word)
 			}
+			return stream.Send(&calculatorpb.ComputeStatisticsRequest{
+				Request: &calculatorpb.ComputeStatisticsRequest_Number{Number: n},
+			})
+		})
+	}
 
-			time.Sleep(500 * time.Millisecond)
+	if *every == 0 {
+		stream, err := c.ComputeStatistics(ctx)
+		if err != nil {
+			return err
 		}
-
-		err := stream.CloseSend()
+		if err := send(stream); err != nil && err != io.EOF {
+			return err
+		}
+		res, err := stream.CloseAndRecv()
 		if err != nil {
-			log.Fatalf("Error closing stream %v", err)
+			return err
 		}
-	}()
+		return opts.print(res, formatStatistics(res))
+	}
 
+	stream, err := c.ComputeRunningStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	recvErr := make(chan error, 1)
 	go func() {
 		for {
 			res, err := stream.Recv()
 			if err == io.EOF {
-				break
+				recvErr <- nil
+				return
 			}
 			if err != nil {
-				log.Fatalf("Err receiving server stream %v", err)
+				recvErr <- err
+				return
+			}
+			text := formatStatistics(res)
+			if !opts.json {
+				text += "\n"
+			}
+			if err := opts.print(res, text); err != nil {
+				recvErr <- err
+				return
 			}
-
-			maximum = res.GetMaximum()
-			log.Printf("received maximum %d", maximum)
 		}
-
-		close(waitc)
 	}()
+	if err := send(stream); err != nil && err != io.EOF {
+		cancel()
+		<-recvErr
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return <-recvErr
+}
 
-	<-waitc
+func formatStatistics(res *calculatorpb.ComputeStatisticsResponse) string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	lines := []string{
+		"count\t" + strconv.FormatInt(res.GetCount(), 10),
+		"min\t" + f(res.GetMin()),
+		"max\t" + f(res.GetMax()),
+		"mean\t" + f(res.GetMean()),
+		"variance\t" + f(res.GetVariance()),
+		"stddev\t" + f(res.GetStddev()),
+		"median\t" + f(res.GetMedian()),
+	}
+	for _, p := range res.GetPercentiles() {
+		lines = append(lines, "p"+f(p.GetPercentile())+"\t"+f(p.GetValue()))
+	}
+	return strings.Join(lines, "\n")
 }
 
-func doErrorUnary(ctx calculatorpb.CalculatorServiceClient) {
-	req := calculatorpb.SquareRootRequest{Number: 25}
-	res, err := ctx.SquareRoot(context.Background(), &req)
+func runWindow(args []string) error {
+	var opts options
+	fs := newFlagSet("window", 0, &opts)
+	agg := fs.String("agg", "max", "aggregation: max, min, sum or mean")
+	size := fs.Int("size", 0, "values per window")
+	slide := fs.Int("slide", 0, "start a window every `n` values; tumbling windows when 0")
+	duration := fs.Duration("duration", 0, "window length, instead of -size")
+	slideDuration := fs.Duration("slide-duration", 0, "start a window every `interval`; tumbling windows when 0")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	aggregation, ok := calculatorpb.Aggregation_value["AGGREGATION_"+strings.ToUpper(*agg)]
+	if !ok || aggregation == 0 {
+		return cli.Usagef("unknown aggregation %q", *agg)
+	}
+	windowOpts := &calculatorpb.WindowOptions{
+		Aggregation: calculatorpb.Aggregation(aggregation),
+		Size:        int32(*size),
+		Slide:       int32(*slide),
+	}
+	switch {
+	case *duration > 0 && (*size != 0 || *slide != 0):
+		return cli.Usagef("-duration cannot be combined with -size or -slide")
+	case *duration > 0:
+		windowOpts.Duration = durationpb.New(*duration)
+		if *slideDuration > 0 {
+			windowOpts.SlideDuration = durationpb.New(*slideDuration)
+		}
+	case *size <= 0:
+		return cli.Usagef("one of -size or -duration is required")
+	case *slideDuration != 0:
+		return cli.Usagef("-slide-duration requires -duration")
+	}
+
+	c, err := dial(&opts)
 	if err != nil {
-		respErr, ok := status.FromError(err)
-		if ok {
-			log.Printf(" %v : %v.", respErr.Code(), respErr.Message())
-		} else {
-			log.Fatalf("Big error calling SquareRoot Server: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.AggregateWindows(ctx)
+	if err != nil {
+		return err
+	}
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if err := opts.print(res, formatWindow(res)); err != nil {
+				recvErr <- err
+				return
+			}
 		}
+	}()
 
-		return
+	sendErr := stream.Send(&calculatorpb.AggregateWindowsRequest{
+		Request: &calculatorpb.AggregateWindowsRequest_Options{Options: windowOpts},
+	})
+	if sendErr == nil {
+		sendErr = words(fs, func(word string) error {
+			n, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return cli.Usagef("invalid number %q", word)
+			}
+			return stream.Send(&calculatorpb.AggregateWindowsRequest{
+				Request: &calculatorpb.AggregateWindowsRequest_Number{Number: n},
+			})
+		})
 	}
+	if sendErr != nil && sendErr != io.EOF {
+		cancel()
+		<-recvErr
+		return sendErr
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	return <-recvErr
+}
 
-	numberRoot := res.GetNumberRoot()
-	log.Printf("received number root %f", numberRoot)
+func formatWindow(res *calculatorpb.AggregateWindowsResponse) string {
+	span := fmt.Sprintf("[%d..%d]", res.GetFirstIndex(), res.GetLastIndex())
+	if res.GetStart() != nil {
+		span = fmt.Sprintf("[%s, %s)",
+			res.GetStart().AsTime().Local().Format("15:04:05.000"),
+			res.GetEnd().AsTime().Local().Format("15:04:05.000"))
+	}
+	return fmt.Sprintf("%s\t%d\t%s", span, res.GetCount(), strconv.FormatFloat(res.GetResult(), 'g', -1, 64))
 }
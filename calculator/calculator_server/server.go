@@ -1,146 +1,104 @@
 package main
 
 import (
-	"context"
+	"expvar"
+	"flag"
 	"fmt"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/grpc/status"
-	"io"
 	"log"
-	"math"
 	"net"
+	"net/http"
+	"time"
 
 	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorserver"
+	"github.com/pandadragoon/grpc-go-course/internal/cache"
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/deadline"
+	"github.com/pandadragoon/grpc-go-course/internal/grpcinfra"
+	"github.com/pandadragoon/grpc-go-course/internal/ratelimit"
+	"github.com/pandadragoon/grpc-go-course/internal/recovery"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
 	"google.golang.org/grpc"
 )
 
-type server struct{}
-
-func (*server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
-	log.Printf("Sum function was invoked with %v\n", req)
-	first_number := req.GetFirstNumber()
-	second_number := req.GetSecondNumber()
-
-	sum_result := first_number + second_number
-
-	res := &calculatorpb.SumResponse{
-		SumResult: sum_result,
-	}
-
-	return res, nil
-}
-
-func (*server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
-	log.Printf("PrimeNumberDecomposition function was invoked with %v\n", req)
-	number := req.GetNumber()
-	var divisor int64 = 2
-
-	for number > 1 {
-		if number%divisor == 0 {
-			err := stream.Send(&calculatorpb.PrimeNumberDecompositionResponse{
-				PrimeFactor: number,
-			})
-			if err != nil {
-				return err
-			}
-			number = number / divisor
-		} else {
-			divisor++
-
-			go func() {
-				fmt.Printf("Divisor has been increased to: %v\n", divisor)
-			}()
-		}
+func main() {
+	addr := flag.String("addr", "0.0.0.0:50051", "address to listen on")
+	debugAddr := flag.String("debug-addr", "", "address to serve /debug/vars on, e.g. localhost:6060; disabled when empty")
+	cacheSize := flag.Int("cache-size", 1024, "number of results to cache; 0 disables the cache")
+	cacheTTL := flag.Duration("cache-ttl", 10*time.Minute, "how long results stay cached; 0 keeps them until evicted")
+	limits := ratelimit.Config{
+		Default: ratelimit.Limit{Rate: 100, Burst: 200},
+		Methods: map[string]ratelimit.Limit{
+			// Factorizing can keep a core busy for the whole deadline.
+			"/calculator.CalculatorService/PrimeNumberDecomposition": {Rate: 5, Burst: 10},
+		},
+		MaxStreams: 32,
 	}
-	return nil
-}
-
-func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
-	log.Println("ComputeAverage function was invoked")
-	sum := float64(0)
-	count := float64(0)
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			err := stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
-				Average: sum / count,
-			})
-			if err != nil {
-				return err
-			}
-			return nil
-		}
-		if err != nil {
-			log.Fatalf("Failed to connect to client stream %v", err)
-			return err
-		}
-		number := float64(req.GetNumber())
-		count++
-		sum += number
+	ratelimit.RegisterFlags(flag.CommandLine, &limits)
+	deadlines := deadline.Config{
+		Limits: deadline.Limits{Default: 10 * time.Second, Max: time.Minute},
+		Methods: map[string]deadline.Limits{
+			"/calculator.CalculatorService/PrimeNumberDecomposition": {Default: 30 * time.Second, Max: 5 * time.Minute},
+			// Client streams last as long as the client keeps sending.
+			"/calculator.CalculatorService/ComputeAverage":           {},
+			"/calculator.CalculatorService/FindMaximum":              {},
+			"/calculator.CalculatorService/ComputeStatistics":        {},
+			"/calculator.CalculatorService/ComputeRunningStatistics": {},
+			"/calculator.CalculatorService/AggregateWindows":         {},
+		},
 	}
-}
-
-func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
-	maximum := int32(0)
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			log.Fatalf("Error recieving client stream %v", err)
-			return err
-		}
-
-		if maximum < req.GetNumber() {
-			maximum = req.GetNumber()
-			err = stream.Send(&calculatorpb.FindMaximumResponse{
-				Maximum: maximum,
-			})
-			if err != nil {
-				log.Fatalf("Error sending response to client %v", err)
-				return err
+	deadline.RegisterFlags(flag.CommandLine, &deadlines)
+	compressionConfig := compression.Config{MinSize: compression.DefaultMinSize}
+	compression.RegisterFlags(flag.CommandLine, &compressionConfig)
+	flag.Parse()
+	compression.Configure(compressionConfig)
+
+	results := cache.New(*cacheSize, *cacheTTL)
+	expvar.Publish("calculator_cache", expvar.Func(func() interface{} {
+		return results.Stats()
+	}))
+	if *debugAddr != "" {
+		go func() {
+			// expvar registers /debug/vars on the default mux.
+			if err := http.ListenAndServe(*debugAddr, nil); err != nil {
+				log.Fatalf("Unable to serve debug endpoints: %v", err)
 			}
-		}
-	}
-
-	return nil
-}
-
-func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
-	fmt.Println("Received SquareRoot RPC")
-	number := req.GetNumber()
-	if number < 0 {
-		return nil, status.Errorf(
-				codes.InvalidArgument,
-				fmt.Sprintf("Received a negative number: %d", number),
-			)
+		}()
 	}
-	numberRoot := math.Sqrt(float64(number))
 
-	res := calculatorpb.SquareRootResponse{
-		NumberRoot: numberRoot,
-	}
-
-	return &res, nil
-}
-
-func main() {
 	fmt.Println("Calculator Server")
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	lis, err := net.Listen("tcp", *addr)
 
 	if err != nil {
 		log.Fatalf("Failed to Listen: %v", err)
 	}
 
-	s := grpc.NewServer()
-
-	calculatorpb.RegisterCalculatorServiceServer(s, &server{})
+	limiter := ratelimit.New(limits)
+	s := grpc.NewServer(
+		grpcinfra.KeepaliveEnforcement(),
+		deadline.ServerOption(deadlines),
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor(),
+			limiter.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			recovery.StreamServerInterceptor(),
+			limiter.StreamServerInterceptor(),
+		),
+	)
+
+	calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New(results))
 	reflection.Register(s)
 
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("calculator.CalculatorService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Unable to serve: %v", err)
 	}
@@ -0,0 +1,47 @@
+// Package calculatorclient is the client library for CalculatorService.
+package calculatorclient
+
+import (
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/client"
+	"google.golang.org/grpc"
+)
+
+const service = "calculator.CalculatorService"
+
+// MethodConfigs returns the default method configuration: Sum and SquareRoot
+// are idempotent and retried, and every unary call gets a 10s deadline. The
+// streaming methods run for as long as the caller keeps them open.
+func MethodConfigs() []client.MethodConfig {
+	retry := client.DefaultRetryPolicy
+	return []client.MethodConfig{
+		{Service: service, Method: "Sum", Timeout: 10 * time.Second, Retry: &retry},
+		{Service: service, Method: "SquareRoot", Timeout: 10 * time.Second, Retry: &retry},
+	}
+}
+
+// Client is a CalculatorServiceClient that owns its connection.
+type Client struct {
+	calculatorpb.CalculatorServiceClient
+	conn *grpc.ClientConn
+}
+
+// New dials target with MethodConfigs and opts. target is anything
+// client.Dial accepts, including client.Target of several replicas.
+func New(target string, opts ...client.Option) (*Client, error) {
+	conn, err := client.Dial(target, client.Service{Name: service, Methods: MethodConfigs()}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		CalculatorServiceClient: calculatorpb.NewCalculatorServiceClient(conn),
+		conn:                    conn,
+	}, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
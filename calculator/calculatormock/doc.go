@@ -0,0 +1,7 @@
+// Package calculatormock provides mocks of the calculator service's client and stream
+// interfaces, for testing code that uses calculatorpb without a server. Client
+// mocks record their calls and answer with programmable functions; stream
+// mocks replay programmed messages and record the ones sent.
+package calculatormock
+
+//go:generate go run ../../internal/cmd/genmock -proto calculator/calculatorpb/calculator.proto -package calculatormock -o mock.go
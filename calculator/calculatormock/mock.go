@@ -0,0 +1,890 @@
+// Code generated by genmock. DO NOT EDIT.
+// source: calculator/calculatorpb/calculator.proto
+
+package calculatormock
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a call recorded by a client mock.
+type Call struct {
+	// Method is the name of the method called, such as "Sum".
+	Method string
+	// Request is the request of a unary or server streaming call, and nil
+	// for calls that send a stream.
+	Request proto.Message
+	// Metadata is the outgoing metadata of the call's context.
+	Metadata metadata.MD
+}
+
+// calls records the calls of a client mock.
+type calls struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (c *calls) record(ctx context.Context, method string, req proto.Message) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Request: req, Metadata: md.Copy()})
+}
+
+// Calls returns the calls made so far, in order.
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+func unprogrammed(method string) error {
+	return status.Errorf(codes.Unimplemented, "mock: no response programmed for %s", method)
+}
+
+// CalculatorServiceClient is a mock calculatorpb.CalculatorServiceClient.
+//
+// Each method calls the function of the same name with a Func suffix, and
+// fails with UNIMPLEMENTED when that is nil. Calls are recorded either way.
+type CalculatorServiceClient struct {
+	calls
+
+	SumFunc                      func(ctx context.Context, in *calculatorpb.SumRequest, opts ...grpc.CallOption) (*calculatorpb.SumResponse, error)
+	PrimeNumberDecompositionFunc func(ctx context.Context, in *calculatorpb.PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (calculatorpb.CalculatorService_PrimeNumberDecompositionClient, error)
+	ComputeAverageFunc           func(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeAverageClient, error)
+	FindMaximumFunc              func(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_FindMaximumClient, error)
+	SquareRootFunc               func(ctx context.Context, in *calculatorpb.SquareRootRequest, opts ...grpc.CallOption) (*calculatorpb.SquareRootResponse, error)
+	BigSumFunc                   func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	BigSubtractFunc              func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	BigMultiplyFunc              func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	BigDivideFunc                func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	BigModuloFunc                func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	BigPowerFunc                 func(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error)
+	EvaluateFunc                 func(ctx context.Context, in *calculatorpb.EvaluateRequest, opts ...grpc.CallOption) (*calculatorpb.EvaluateResponse, error)
+	ComputeStatisticsFunc        func(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeStatisticsClient, error)
+	ComputeRunningStatisticsFunc func(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeRunningStatisticsClient, error)
+	AggregateWindowsFunc         func(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_AggregateWindowsClient, error)
+	DoubleSumFunc                func(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error)
+	DoubleSubtractFunc           func(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error)
+	DoubleMultiplyFunc           func(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error)
+	DoubleDivideFunc             func(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error)
+	DoublePowerFunc              func(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error)
+	DoubleSquareRootFunc         func(ctx context.Context, in *calculatorpb.DoubleSquareRootRequest, opts ...grpc.CallOption) (*calculatorpb.SquareRootResponse, error)
+	DotProductFunc               func(ctx context.Context, in *calculatorpb.DotProductRequest, opts ...grpc.CallOption) (*calculatorpb.DotProductResponse, error)
+	MatrixMultiplyFunc           func(ctx context.Context, in *calculatorpb.MatrixMultiplyRequest, opts ...grpc.CallOption) (*calculatorpb.MatrixMultiplyResponse, error)
+	DeterminantFunc              func(ctx context.Context, in *calculatorpb.DeterminantRequest, opts ...grpc.CallOption) (*calculatorpb.DeterminantResponse, error)
+	SolveLinearSystemFunc        func(ctx context.Context, in *calculatorpb.SolveLinearSystemRequest, opts ...grpc.CallOption) (*calculatorpb.SolveLinearSystemResponse, error)
+}
+
+var _ calculatorpb.CalculatorServiceClient = (*CalculatorServiceClient)(nil)
+
+func (m *CalculatorServiceClient) Sum(ctx context.Context, in *calculatorpb.SumRequest, opts ...grpc.CallOption) (*calculatorpb.SumResponse, error) {
+	m.record(ctx, "Sum", in)
+	if m.SumFunc == nil {
+		return nil, unprogrammed("Sum")
+	}
+	return m.SumFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *calculatorpb.PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (calculatorpb.CalculatorService_PrimeNumberDecompositionClient, error) {
+	m.record(ctx, "PrimeNumberDecomposition", in)
+	if m.PrimeNumberDecompositionFunc == nil {
+		return nil, unprogrammed("PrimeNumberDecomposition")
+	}
+	return m.PrimeNumberDecompositionFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeAverageClient, error) {
+	m.record(ctx, "ComputeAverage", nil)
+	if m.ComputeAverageFunc == nil {
+		return nil, unprogrammed("ComputeAverage")
+	}
+	return m.ComputeAverageFunc(ctx, opts...)
+}
+
+func (m *CalculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_FindMaximumClient, error) {
+	m.record(ctx, "FindMaximum", nil)
+	if m.FindMaximumFunc == nil {
+		return nil, unprogrammed("FindMaximum")
+	}
+	return m.FindMaximumFunc(ctx, opts...)
+}
+
+func (m *CalculatorServiceClient) SquareRoot(ctx context.Context, in *calculatorpb.SquareRootRequest, opts ...grpc.CallOption) (*calculatorpb.SquareRootResponse, error) {
+	m.record(ctx, "SquareRoot", in)
+	if m.SquareRootFunc == nil {
+		return nil, unprogrammed("SquareRoot")
+	}
+	return m.SquareRootFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigSum(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigSum", in)
+	if m.BigSumFunc == nil {
+		return nil, unprogrammed("BigSum")
+	}
+	return m.BigSumFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigSubtract(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigSubtract", in)
+	if m.BigSubtractFunc == nil {
+		return nil, unprogrammed("BigSubtract")
+	}
+	return m.BigSubtractFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigMultiply(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigMultiply", in)
+	if m.BigMultiplyFunc == nil {
+		return nil, unprogrammed("BigMultiply")
+	}
+	return m.BigMultiplyFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigDivide(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigDivide", in)
+	if m.BigDivideFunc == nil {
+		return nil, unprogrammed("BigDivide")
+	}
+	return m.BigDivideFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigModulo(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigModulo", in)
+	if m.BigModuloFunc == nil {
+		return nil, unprogrammed("BigModulo")
+	}
+	return m.BigModuloFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) BigPower(ctx context.Context, in *calculatorpb.BigNumberRequest, opts ...grpc.CallOption) (*calculatorpb.BigNumberResponse, error) {
+	m.record(ctx, "BigPower", in)
+	if m.BigPowerFunc == nil {
+		return nil, unprogrammed("BigPower")
+	}
+	return m.BigPowerFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) Evaluate(ctx context.Context, in *calculatorpb.EvaluateRequest, opts ...grpc.CallOption) (*calculatorpb.EvaluateResponse, error) {
+	m.record(ctx, "Evaluate", in)
+	if m.EvaluateFunc == nil {
+		return nil, unprogrammed("Evaluate")
+	}
+	return m.EvaluateFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeStatisticsClient, error) {
+	m.record(ctx, "ComputeStatistics", nil)
+	if m.ComputeStatisticsFunc == nil {
+		return nil, unprogrammed("ComputeStatistics")
+	}
+	return m.ComputeStatisticsFunc(ctx, opts...)
+}
+
+func (m *CalculatorServiceClient) ComputeRunningStatistics(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_ComputeRunningStatisticsClient, error) {
+	m.record(ctx, "ComputeRunningStatistics", nil)
+	if m.ComputeRunningStatisticsFunc == nil {
+		return nil, unprogrammed("ComputeRunningStatistics")
+	}
+	return m.ComputeRunningStatisticsFunc(ctx, opts...)
+}
+
+func (m *CalculatorServiceClient) AggregateWindows(ctx context.Context, opts ...grpc.CallOption) (calculatorpb.CalculatorService_AggregateWindowsClient, error) {
+	m.record(ctx, "AggregateWindows", nil)
+	if m.AggregateWindowsFunc == nil {
+		return nil, unprogrammed("AggregateWindows")
+	}
+	return m.AggregateWindowsFunc(ctx, opts...)
+}
+
+func (m *CalculatorServiceClient) DoubleSum(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error) {
+	m.record(ctx, "DoubleSum", in)
+	if m.DoubleSumFunc == nil {
+		return nil, unprogrammed("DoubleSum")
+	}
+	return m.DoubleSumFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DoubleSubtract(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error) {
+	m.record(ctx, "DoubleSubtract", in)
+	if m.DoubleSubtractFunc == nil {
+		return nil, unprogrammed("DoubleSubtract")
+	}
+	return m.DoubleSubtractFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DoubleMultiply(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error) {
+	m.record(ctx, "DoubleMultiply", in)
+	if m.DoubleMultiplyFunc == nil {
+		return nil, unprogrammed("DoubleMultiply")
+	}
+	return m.DoubleMultiplyFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DoubleDivide(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error) {
+	m.record(ctx, "DoubleDivide", in)
+	if m.DoubleDivideFunc == nil {
+		return nil, unprogrammed("DoubleDivide")
+	}
+	return m.DoubleDivideFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DoublePower(ctx context.Context, in *calculatorpb.DoubleRequest, opts ...grpc.CallOption) (*calculatorpb.DoubleResponse, error) {
+	m.record(ctx, "DoublePower", in)
+	if m.DoublePowerFunc == nil {
+		return nil, unprogrammed("DoublePower")
+	}
+	return m.DoublePowerFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DoubleSquareRoot(ctx context.Context, in *calculatorpb.DoubleSquareRootRequest, opts ...grpc.CallOption) (*calculatorpb.SquareRootResponse, error) {
+	m.record(ctx, "DoubleSquareRoot", in)
+	if m.DoubleSquareRootFunc == nil {
+		return nil, unprogrammed("DoubleSquareRoot")
+	}
+	return m.DoubleSquareRootFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) DotProduct(ctx context.Context, in *calculatorpb.DotProductRequest, opts ...grpc.CallOption) (*calculatorpb.DotProductResponse, error) {
+	m.record(ctx, "DotProduct", in)
+	if m.DotProductFunc == nil {
+		return nil, unprogrammed("DotProduct")
+	}
+	return m.DotProductFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) MatrixMultiply(ctx context.Context, in *calculatorpb.MatrixMultiplyRequest, opts ...grpc.CallOption) (*calculatorpb.MatrixMultiplyResponse, error) {
+	m.record(ctx, "MatrixMultiply", in)
+	if m.MatrixMultiplyFunc == nil {
+		return nil, unprogrammed("MatrixMultiply")
+	}
+	return m.MatrixMultiplyFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) Determinant(ctx context.Context, in *calculatorpb.DeterminantRequest, opts ...grpc.CallOption) (*calculatorpb.DeterminantResponse, error) {
+	m.record(ctx, "Determinant", in)
+	if m.DeterminantFunc == nil {
+		return nil, unprogrammed("Determinant")
+	}
+	return m.DeterminantFunc(ctx, in, opts...)
+}
+
+func (m *CalculatorServiceClient) SolveLinearSystem(ctx context.Context, in *calculatorpb.SolveLinearSystemRequest, opts ...grpc.CallOption) (*calculatorpb.SolveLinearSystemResponse, error) {
+	m.record(ctx, "SolveLinearSystem", in)
+	if m.SolveLinearSystemFunc == nil {
+		return nil, unprogrammed("SolveLinearSystem")
+	}
+	return m.SolveLinearSystemFunc(ctx, in, opts...)
+}
+
+// ClientStream implements grpc.ClientStream for the client stream mocks.
+// The messages of a stream go through its typed methods, so SendMsg and
+// RecvMsg fail.
+type ClientStream struct {
+	// Ctx is returned by Context; context.Background() when nil.
+	Ctx context.Context
+	// HeaderMD and HeaderErr are returned by Header.
+	HeaderMD  metadata.MD
+	HeaderErr error
+	// TrailerMD is returned by Trailer.
+	TrailerMD metadata.MD
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *ClientStream) Header() (metadata.MD, error) { return s.HeaderMD, s.HeaderErr }
+func (s *ClientStream) Trailer() metadata.MD         { return s.TrailerMD }
+
+func (s *ClientStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+// CloseSend marks the sending side closed.
+func (s *ClientStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// Closed reports whether the client closed its sending side.
+func (s *ClientStream) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *ClientStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ClientStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+
+// ServerStream implements grpc.ServerStream for the server stream mocks,
+// recording the metadata a handler sets.
+type ServerStream struct {
+	// Ctx is returned by Context; context.Background() when nil. Incoming
+	// metadata for the handler goes in it.
+	Ctx context.Context
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *ServerStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+func (s *ServerStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *ServerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *ServerStream) SetTrailer(md metadata.MD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// Header returns the header metadata the handler set or sent.
+func (s *ServerStream) Header() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Copy()
+}
+
+// Trailer returns the trailer metadata the handler set.
+func (s *ServerStream) Trailer() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trailer.Copy()
+}
+
+func (s *ServerStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ServerStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+
+// CalculatorService_PrimeNumberDecompositionClient is a mock calculatorpb.CalculatorService_PrimeNumberDecompositionClient.
+type CalculatorService_PrimeNumberDecompositionClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*calculatorpb.PrimeNumberDecompositionResponse
+	Err       error
+	received  int
+}
+
+var _ calculatorpb.CalculatorService_PrimeNumberDecompositionClient = (*CalculatorService_PrimeNumberDecompositionClient)(nil)
+
+func (s *CalculatorService_PrimeNumberDecompositionClient) Recv() (*calculatorpb.PrimeNumberDecompositionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// CalculatorService_PrimeNumberDecompositionServer is a mock calculatorpb.CalculatorService_PrimeNumberDecompositionServer.
+// Handlers can be called with it directly.
+type CalculatorService_PrimeNumberDecompositionServer struct {
+	ServerStream
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*calculatorpb.PrimeNumberDecompositionResponse
+}
+
+var _ calculatorpb.CalculatorService_PrimeNumberDecompositionServer = (*CalculatorService_PrimeNumberDecompositionServer)(nil)
+
+func (s *CalculatorService_PrimeNumberDecompositionServer) Send(m *calculatorpb.PrimeNumberDecompositionResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_PrimeNumberDecompositionServer) Sent() []*calculatorpb.PrimeNumberDecompositionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.PrimeNumberDecompositionResponse(nil), s.sent...)
+}
+
+// CalculatorService_ComputeAverageClient is a mock calculatorpb.CalculatorService_ComputeAverageClient.
+type CalculatorService_ComputeAverageClient struct {
+	ClientStream
+	// Response and Err are returned by CloseAndRecv.
+	Response *calculatorpb.ComputeAverageResponse
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*calculatorpb.ComputeAverageRequest
+}
+
+var _ calculatorpb.CalculatorService_ComputeAverageClient = (*CalculatorService_ComputeAverageClient)(nil)
+
+func (s *CalculatorService_ComputeAverageClient) Send(m *calculatorpb.ComputeAverageRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_ComputeAverageClient) Sent() []*calculatorpb.ComputeAverageRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeAverageRequest(nil), s.sent...)
+}
+
+func (s *CalculatorService_ComputeAverageClient) CloseAndRecv() (*calculatorpb.ComputeAverageResponse, error) {
+	s.CloseSend()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return s.Response, nil
+}
+
+// CalculatorService_ComputeAverageServer is a mock calculatorpb.CalculatorService_ComputeAverageServer.
+// Handlers can be called with it directly.
+type CalculatorService_ComputeAverageServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*calculatorpb.ComputeAverageRequest
+	Err      error
+	// SendErr is returned by SendAndClose without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.ComputeAverageResponse
+	received int
+}
+
+var _ calculatorpb.CalculatorService_ComputeAverageServer = (*CalculatorService_ComputeAverageServer)(nil)
+
+func (s *CalculatorService_ComputeAverageServer) Recv() (*calculatorpb.ComputeAverageRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *CalculatorService_ComputeAverageServer) SendAndClose(m *calculatorpb.ComputeAverageResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the response sent with SendAndClose, if any.
+func (s *CalculatorService_ComputeAverageServer) Sent() []*calculatorpb.ComputeAverageResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeAverageResponse(nil), s.sent...)
+}
+
+// CalculatorService_FindMaximumClient is a mock calculatorpb.CalculatorService_FindMaximumClient.
+type CalculatorService_FindMaximumClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*calculatorpb.FindMaximumResponse
+	Err       error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.FindMaximumRequest
+	received int
+}
+
+var _ calculatorpb.CalculatorService_FindMaximumClient = (*CalculatorService_FindMaximumClient)(nil)
+
+func (s *CalculatorService_FindMaximumClient) Send(m *calculatorpb.FindMaximumRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_FindMaximumClient) Sent() []*calculatorpb.FindMaximumRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.FindMaximumRequest(nil), s.sent...)
+}
+
+func (s *CalculatorService_FindMaximumClient) Recv() (*calculatorpb.FindMaximumResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// CalculatorService_FindMaximumServer is a mock calculatorpb.CalculatorService_FindMaximumServer.
+// Handlers can be called with it directly.
+type CalculatorService_FindMaximumServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*calculatorpb.FindMaximumRequest
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.FindMaximumResponse
+	received int
+}
+
+var _ calculatorpb.CalculatorService_FindMaximumServer = (*CalculatorService_FindMaximumServer)(nil)
+
+func (s *CalculatorService_FindMaximumServer) Recv() (*calculatorpb.FindMaximumRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *CalculatorService_FindMaximumServer) Send(m *calculatorpb.FindMaximumResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_FindMaximumServer) Sent() []*calculatorpb.FindMaximumResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.FindMaximumResponse(nil), s.sent...)
+}
+
+// CalculatorService_ComputeStatisticsClient is a mock calculatorpb.CalculatorService_ComputeStatisticsClient.
+type CalculatorService_ComputeStatisticsClient struct {
+	ClientStream
+	// Response and Err are returned by CloseAndRecv.
+	Response *calculatorpb.ComputeStatisticsResponse
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*calculatorpb.ComputeStatisticsRequest
+}
+
+var _ calculatorpb.CalculatorService_ComputeStatisticsClient = (*CalculatorService_ComputeStatisticsClient)(nil)
+
+func (s *CalculatorService_ComputeStatisticsClient) Send(m *calculatorpb.ComputeStatisticsRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_ComputeStatisticsClient) Sent() []*calculatorpb.ComputeStatisticsRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeStatisticsRequest(nil), s.sent...)
+}
+
+func (s *CalculatorService_ComputeStatisticsClient) CloseAndRecv() (*calculatorpb.ComputeStatisticsResponse, error) {
+	s.CloseSend()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return s.Response, nil
+}
+
+// CalculatorService_ComputeStatisticsServer is a mock calculatorpb.CalculatorService_ComputeStatisticsServer.
+// Handlers can be called with it directly.
+type CalculatorService_ComputeStatisticsServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*calculatorpb.ComputeStatisticsRequest
+	Err      error
+	// SendErr is returned by SendAndClose without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.ComputeStatisticsResponse
+	received int
+}
+
+var _ calculatorpb.CalculatorService_ComputeStatisticsServer = (*CalculatorService_ComputeStatisticsServer)(nil)
+
+func (s *CalculatorService_ComputeStatisticsServer) Recv() (*calculatorpb.ComputeStatisticsRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *CalculatorService_ComputeStatisticsServer) SendAndClose(m *calculatorpb.ComputeStatisticsResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the response sent with SendAndClose, if any.
+func (s *CalculatorService_ComputeStatisticsServer) Sent() []*calculatorpb.ComputeStatisticsResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeStatisticsResponse(nil), s.sent...)
+}
+
+// CalculatorService_ComputeRunningStatisticsClient is a mock calculatorpb.CalculatorService_ComputeRunningStatisticsClient.
+type CalculatorService_ComputeRunningStatisticsClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*calculatorpb.ComputeStatisticsResponse
+	Err       error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.ComputeStatisticsRequest
+	received int
+}
+
+var _ calculatorpb.CalculatorService_ComputeRunningStatisticsClient = (*CalculatorService_ComputeRunningStatisticsClient)(nil)
+
+func (s *CalculatorService_ComputeRunningStatisticsClient) Send(m *calculatorpb.ComputeStatisticsRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_ComputeRunningStatisticsClient) Sent() []*calculatorpb.ComputeStatisticsRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeStatisticsRequest(nil), s.sent...)
+}
+
+func (s *CalculatorService_ComputeRunningStatisticsClient) Recv() (*calculatorpb.ComputeStatisticsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// CalculatorService_ComputeRunningStatisticsServer is a mock calculatorpb.CalculatorService_ComputeRunningStatisticsServer.
+// Handlers can be called with it directly.
+type CalculatorService_ComputeRunningStatisticsServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*calculatorpb.ComputeStatisticsRequest
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.ComputeStatisticsResponse
+	received int
+}
+
+var _ calculatorpb.CalculatorService_ComputeRunningStatisticsServer = (*CalculatorService_ComputeRunningStatisticsServer)(nil)
+
+func (s *CalculatorService_ComputeRunningStatisticsServer) Recv() (*calculatorpb.ComputeStatisticsRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *CalculatorService_ComputeRunningStatisticsServer) Send(m *calculatorpb.ComputeStatisticsResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_ComputeRunningStatisticsServer) Sent() []*calculatorpb.ComputeStatisticsResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.ComputeStatisticsResponse(nil), s.sent...)
+}
+
+// CalculatorService_AggregateWindowsClient is a mock calculatorpb.CalculatorService_AggregateWindowsClient.
+type CalculatorService_AggregateWindowsClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*calculatorpb.AggregateWindowsResponse
+	Err       error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.AggregateWindowsRequest
+	received int
+}
+
+var _ calculatorpb.CalculatorService_AggregateWindowsClient = (*CalculatorService_AggregateWindowsClient)(nil)
+
+func (s *CalculatorService_AggregateWindowsClient) Send(m *calculatorpb.AggregateWindowsRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_AggregateWindowsClient) Sent() []*calculatorpb.AggregateWindowsRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.AggregateWindowsRequest(nil), s.sent...)
+}
+
+func (s *CalculatorService_AggregateWindowsClient) Recv() (*calculatorpb.AggregateWindowsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// CalculatorService_AggregateWindowsServer is a mock calculatorpb.CalculatorService_AggregateWindowsServer.
+// Handlers can be called with it directly.
+type CalculatorService_AggregateWindowsServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*calculatorpb.AggregateWindowsRequest
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*calculatorpb.AggregateWindowsResponse
+	received int
+}
+
+var _ calculatorpb.CalculatorService_AggregateWindowsServer = (*CalculatorService_AggregateWindowsServer)(nil)
+
+func (s *CalculatorService_AggregateWindowsServer) Recv() (*calculatorpb.AggregateWindowsRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *CalculatorService_AggregateWindowsServer) Send(m *calculatorpb.AggregateWindowsResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *CalculatorService_AggregateWindowsServer) Sent() []*calculatorpb.AggregateWindowsResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*calculatorpb.AggregateWindowsResponse(nil), s.sent...)
+}
@@ -0,0 +1,1193 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.15.5
+// source: calculator/calculatorpb/calculator.proto
+
+package calculatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CalculatorServiceClient interface {
+	Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
+	PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error)
+	ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error)
+	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error)
+	SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// Arbitrary precision arithmetic
+	BigSum(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	BigSubtract(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	BigMultiply(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	// Integer division truncated towards zero
+	BigDivide(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	// Euclidean modulus, always between 0 and |second_number|
+	BigModulo(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	// first_number raised to the non-negative power second_number
+	BigPower(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error)
+	// Expression evaluation; parse and evaluation errors are INVALID_ARGUMENT
+	// with the position of the offending token in the error details
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	// Descriptive statistics of the streamed numbers; an empty stream is
+	// INVALID_ARGUMENT
+	ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error)
+	// Statistics of the numbers so far, after every emit_every numbers and
+	// once more at the end for any numbers since the last response
+	ComputeRunningStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeRunningStatisticsClient, error)
+	// Aggregates over count or time windows, one response per window as it
+	// closes; windows still open when the stream ends are reported then
+	AggregateWindows(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_AggregateWindowsClient, error)
+	// Double precision arithmetic; operands must be finite and results that
+	// overflow are OUT_OF_RANGE
+	DoubleSum(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error)
+	DoubleSubtract(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error)
+	DoubleMultiply(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error)
+	DoubleDivide(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error)
+	DoublePower(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error)
+	DoubleSquareRoot(ctx context.Context, in *DoubleSquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// Linear algebra; mismatched dimensions are INVALID_ARGUMENT and a
+	// singular matrix passed to SolveLinearSystem is FAILED_PRECONDITION
+	DotProduct(ctx context.Context, in *DotProductRequest, opts ...grpc.CallOption) (*DotProductResponse, error)
+	MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixMultiplyResponse, error)
+	Determinant(ctx context.Context, in *DeterminantRequest, opts ...grpc.CallOption) (*DeterminantResponse, error)
+	SolveLinearSystem(ctx context.Context, in *SolveLinearSystemRequest, opts ...grpc.CallOption) (*SolveLinearSystemResponse, error)
+}
+
+type calculatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalculatorServiceClient(cc grpc.ClientConnInterface) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
+	out := new(SumResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Sum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[0], "/calculator.CalculatorService/PrimeNumberDecomposition", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServicePrimeNumberDecompositionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CalculatorService_PrimeNumberDecompositionClient interface {
+	Recv() (*PrimeNumberDecompositionResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServicePrimeNumberDecompositionClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServicePrimeNumberDecompositionClient) Recv() (*PrimeNumberDecompositionResponse, error) {
+	m := new(PrimeNumberDecompositionResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[1], "/calculator.CalculatorService/ComputeAverage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeAverageClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeAverageClient interface {
+	Send(*ComputeAverageRequest) error
+	CloseAndRecv() (*ComputeAverageResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeAverageClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeAverageClient) Send(m *ComputeAverageRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeAverageClient) CloseAndRecv() (*ComputeAverageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ComputeAverageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[2], "/calculator.CalculatorService/FindMaximum", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceFindMaximumClient{stream}
+	return x, nil
+}
+
+type CalculatorService_FindMaximumClient interface {
+	Send(*FindMaximumRequest) error
+	Recv() (*FindMaximumResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceFindMaximumClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceFindMaximumClient) Send(m *FindMaximumRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
+	m := new(FindMaximumResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigSum(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigSum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigSubtract(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigSubtract", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigMultiply(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigMultiply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigDivide(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigDivide", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigModulo(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigModulo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) BigPower(ctx context.Context, in *BigNumberRequest, opts ...grpc.CallOption) (*BigNumberResponse, error) {
+	out := new(BigNumberResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/BigPower", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Evaluate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComputeStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[3], "/calculator.CalculatorService/ComputeStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeStatisticsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeStatisticsClient interface {
+	Send(*ComputeStatisticsRequest) error
+	CloseAndRecv() (*ComputeStatisticsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeStatisticsClient) Send(m *ComputeStatisticsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsClient) CloseAndRecv() (*ComputeStatisticsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ComputeStatisticsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) ComputeRunningStatistics(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeRunningStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[4], "/calculator.CalculatorService/ComputeRunningStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceComputeRunningStatisticsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_ComputeRunningStatisticsClient interface {
+	Send(*ComputeStatisticsRequest) error
+	Recv() (*ComputeStatisticsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceComputeRunningStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceComputeRunningStatisticsClient) Send(m *ComputeStatisticsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeRunningStatisticsClient) Recv() (*ComputeStatisticsResponse, error) {
+	m := new(ComputeStatisticsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) AggregateWindows(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_AggregateWindowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[5], "/calculator.CalculatorService/AggregateWindows", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calculatorServiceAggregateWindowsClient{stream}
+	return x, nil
+}
+
+type CalculatorService_AggregateWindowsClient interface {
+	Send(*AggregateWindowsRequest) error
+	Recv() (*AggregateWindowsResponse, error)
+	grpc.ClientStream
+}
+
+type calculatorServiceAggregateWindowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calculatorServiceAggregateWindowsClient) Send(m *AggregateWindowsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *calculatorServiceAggregateWindowsClient) Recv() (*AggregateWindowsResponse, error) {
+	m := new(AggregateWindowsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *calculatorServiceClient) DoubleSum(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error) {
+	out := new(DoubleResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoubleSum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DoubleSubtract(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error) {
+	out := new(DoubleResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoubleSubtract", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DoubleMultiply(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error) {
+	out := new(DoubleResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoubleMultiply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DoubleDivide(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error) {
+	out := new(DoubleResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoubleDivide", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DoublePower(ctx context.Context, in *DoubleRequest, opts ...grpc.CallOption) (*DoubleResponse, error) {
+	out := new(DoubleResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoublePower", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DoubleSquareRoot(ctx context.Context, in *DoubleSquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DoubleSquareRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) DotProduct(ctx context.Context, in *DotProductRequest, opts ...grpc.CallOption) (*DotProductResponse, error) {
+	out := new(DotProductResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/DotProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixMultiplyResponse, error) {
+	out := new(MatrixMultiplyResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/MatrixMultiply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Determinant(ctx context.Context, in *DeterminantRequest, opts ...grpc.CallOption) (*DeterminantResponse, error) {
+	out := new(DeterminantResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Determinant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) SolveLinearSystem(ctx context.Context, in *SolveLinearSystemRequest, opts ...grpc.CallOption) (*SolveLinearSystemResponse, error) {
+	out := new(SolveLinearSystemResponse)
+	err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SolveLinearSystem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorServiceServer is the server API for CalculatorService service.
+// All implementations must embed UnimplementedCalculatorServiceServer
+// for forward compatibility
+type CalculatorServiceServer interface {
+	Sum(context.Context, *SumRequest) (*SumResponse, error)
+	PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalculatorService_PrimeNumberDecompositionServer) error
+	ComputeAverage(CalculatorService_ComputeAverageServer) error
+	FindMaximum(CalculatorService_FindMaximumServer) error
+	SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+	// Arbitrary precision arithmetic
+	BigSum(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	BigSubtract(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	BigMultiply(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	// Integer division truncated towards zero
+	BigDivide(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	// Euclidean modulus, always between 0 and |second_number|
+	BigModulo(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	// first_number raised to the non-negative power second_number
+	BigPower(context.Context, *BigNumberRequest) (*BigNumberResponse, error)
+	// Expression evaluation; parse and evaluation errors are INVALID_ARGUMENT
+	// with the position of the offending token in the error details
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	// Descriptive statistics of the streamed numbers; an empty stream is
+	// INVALID_ARGUMENT
+	ComputeStatistics(CalculatorService_ComputeStatisticsServer) error
+	// Statistics of the numbers so far, after every emit_every numbers and
+	// once more at the end for any numbers since the last response
+	ComputeRunningStatistics(CalculatorService_ComputeRunningStatisticsServer) error
+	// Aggregates over count or time windows, one response per window as it
+	// closes; windows still open when the stream ends are reported then
+	AggregateWindows(CalculatorService_AggregateWindowsServer) error
+	// Double precision arithmetic; operands must be finite and results that
+	// overflow are OUT_OF_RANGE
+	DoubleSum(context.Context, *DoubleRequest) (*DoubleResponse, error)
+	DoubleSubtract(context.Context, *DoubleRequest) (*DoubleResponse, error)
+	DoubleMultiply(context.Context, *DoubleRequest) (*DoubleResponse, error)
+	DoubleDivide(context.Context, *DoubleRequest) (*DoubleResponse, error)
+	DoublePower(context.Context, *DoubleRequest) (*DoubleResponse, error)
+	DoubleSquareRoot(context.Context, *DoubleSquareRootRequest) (*SquareRootResponse, error)
+	// Linear algebra; mismatched dimensions are INVALID_ARGUMENT and a
+	// singular matrix passed to SolveLinearSystem is FAILED_PRECONDITION
+	DotProduct(context.Context, *DotProductRequest) (*DotProductResponse, error)
+	MatrixMultiply(context.Context, *MatrixMultiplyRequest) (*MatrixMultiplyResponse, error)
+	Determinant(context.Context, *DeterminantRequest) (*DeterminantResponse, error)
+	SolveLinearSystem(context.Context, *SolveLinearSystemRequest) (*SolveLinearSystemResponse, error)
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+// UnimplementedCalculatorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCalculatorServiceServer struct {
+}
+
+func (UnimplementedCalculatorServiceServer) Sum(context.Context, *SumRequest) (*SumResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalculatorService_PrimeNumberDecompositionServer) error {
+	return status.Errorf(codes.Unimplemented, "method PrimeNumberDecomposition not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComputeAverage(CalculatorService_ComputeAverageServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeAverage not implemented")
+}
+func (UnimplementedCalculatorServiceServer) FindMaximum(CalculatorService_FindMaximumServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindMaximum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigSum(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigSum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigSubtract(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigSubtract not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigMultiply(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigMultiply not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigDivide(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigDivide not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigModulo(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigModulo not implemented")
+}
+func (UnimplementedCalculatorServiceServer) BigPower(context.Context, *BigNumberRequest) (*BigNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BigPower not implemented")
+}
+func (UnimplementedCalculatorServiceServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComputeStatistics(CalculatorService_ComputeStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeStatistics not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComputeRunningStatistics(CalculatorService_ComputeRunningStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeRunningStatistics not implemented")
+}
+func (UnimplementedCalculatorServiceServer) AggregateWindows(CalculatorService_AggregateWindowsServer) error {
+	return status.Errorf(codes.Unimplemented, "method AggregateWindows not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoubleSum(context.Context, *DoubleRequest) (*DoubleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleSum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoubleSubtract(context.Context, *DoubleRequest) (*DoubleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleSubtract not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoubleMultiply(context.Context, *DoubleRequest) (*DoubleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleMultiply not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoubleDivide(context.Context, *DoubleRequest) (*DoubleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleDivide not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoublePower(context.Context, *DoubleRequest) (*DoubleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoublePower not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DoubleSquareRoot(context.Context, *DoubleSquareRootRequest) (*SquareRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DoubleSquareRoot not implemented")
+}
+func (UnimplementedCalculatorServiceServer) DotProduct(context.Context, *DotProductRequest) (*DotProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DotProduct not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixMultiply(context.Context, *MatrixMultiplyRequest) (*MatrixMultiplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixMultiply not implemented")
+}
+func (UnimplementedCalculatorServiceServer) Determinant(context.Context, *DeterminantRequest) (*DeterminantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Determinant not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SolveLinearSystem(context.Context, *SolveLinearSystemRequest) (*SolveLinearSystemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SolveLinearSystem not implemented")
+}
+func (UnimplementedCalculatorServiceServer) mustEmbedUnimplementedCalculatorServiceServer() {}
+
+// UnsafeCalculatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalculatorServiceServer will
+// result in compilation errors.
+type UnsafeCalculatorServiceServer interface {
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+func RegisterCalculatorServiceServer(s grpc.ServiceRegistrar, srv CalculatorServiceServer) {
+	s.RegisterService(&CalculatorService_ServiceDesc, srv)
+}
+
+func _CalculatorService_Sum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Sum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Sum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Sum(ctx, req.(*SumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_PrimeNumberDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrimeNumberDecompositionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).PrimeNumberDecomposition(m, &calculatorServicePrimeNumberDecompositionServer{stream})
+}
+
+type CalculatorService_PrimeNumberDecompositionServer interface {
+	Send(*PrimeNumberDecompositionResponse) error
+	grpc.ServerStream
+}
+
+type calculatorServicePrimeNumberDecompositionServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServicePrimeNumberDecompositionServer) Send(m *PrimeNumberDecompositionResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CalculatorService_ComputeAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeAverage(&calculatorServiceComputeAverageServer{stream})
+}
+
+type CalculatorService_ComputeAverageServer interface {
+	SendAndClose(*ComputeAverageResponse) error
+	Recv() (*ComputeAverageRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceComputeAverageServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceComputeAverageServer) SendAndClose(m *ComputeAverageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeAverageServer) Recv() (*ComputeAverageRequest, error) {
+	m := new(ComputeAverageRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).FindMaximum(&calculatorServiceFindMaximumServer{stream})
+}
+
+type CalculatorService_FindMaximumServer interface {
+	Send(*FindMaximumResponse) error
+	Recv() (*FindMaximumRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceFindMaximumServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceFindMaximumServer) Send(m *FindMaximumResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceFindMaximumServer) Recv() (*FindMaximumRequest, error) {
+	m := new(FindMaximumRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SquareRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/SquareRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SquareRoot(ctx, req.(*SquareRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigSum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigSum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigSum(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigSubtract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigSubtract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigSubtract",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigSubtract(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigMultiply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigMultiply(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigDivide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigDivide",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigDivide(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigModulo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigModulo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigModulo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigModulo(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_BigPower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BigNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).BigPower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/BigPower",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).BigPower(ctx, req.(*BigNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Evaluate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComputeStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeStatistics(&calculatorServiceComputeStatisticsServer{stream})
+}
+
+type CalculatorService_ComputeStatisticsServer interface {
+	SendAndClose(*ComputeStatisticsResponse) error
+	Recv() (*ComputeStatisticsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceComputeStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceComputeStatisticsServer) SendAndClose(m *ComputeStatisticsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeStatisticsServer) Recv() (*ComputeStatisticsRequest, error) {
+	m := new(ComputeStatisticsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_ComputeRunningStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeRunningStatistics(&calculatorServiceComputeRunningStatisticsServer{stream})
+}
+
+type CalculatorService_ComputeRunningStatisticsServer interface {
+	Send(*ComputeStatisticsResponse) error
+	Recv() (*ComputeStatisticsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceComputeRunningStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceComputeRunningStatisticsServer) Send(m *ComputeStatisticsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceComputeRunningStatisticsServer) Recv() (*ComputeStatisticsRequest, error) {
+	m := new(ComputeStatisticsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_AggregateWindows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).AggregateWindows(&calculatorServiceAggregateWindowsServer{stream})
+}
+
+type CalculatorService_AggregateWindowsServer interface {
+	Send(*AggregateWindowsResponse) error
+	Recv() (*AggregateWindowsRequest, error)
+	grpc.ServerStream
+}
+
+type calculatorServiceAggregateWindowsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calculatorServiceAggregateWindowsServer) Send(m *AggregateWindowsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *calculatorServiceAggregateWindowsServer) Recv() (*AggregateWindowsRequest, error) {
+	m := new(AggregateWindowsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CalculatorService_DoubleSum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoubleSum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoubleSum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoubleSum(ctx, req.(*DoubleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DoubleSubtract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoubleSubtract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoubleSubtract",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoubleSubtract(ctx, req.(*DoubleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DoubleMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoubleMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoubleMultiply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoubleMultiply(ctx, req.(*DoubleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DoubleDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoubleDivide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoubleDivide",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoubleDivide(ctx, req.(*DoubleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DoublePower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoublePower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoublePower",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoublePower(ctx, req.(*DoubleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DoubleSquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoubleSquareRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DoubleSquareRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DoubleSquareRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DoubleSquareRoot(ctx, req.(*DoubleSquareRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_DotProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DotProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).DotProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/DotProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).DotProduct(ctx, req.(*DotProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixMultiplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/MatrixMultiply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, req.(*MatrixMultiplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Determinant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeterminantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Determinant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/Determinant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Determinant(ctx, req.(*DeterminantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_SolveLinearSystem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveLinearSystemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SolveLinearSystem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/calculator.CalculatorService/SolveLinearSystem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SolveLinearSystem(ctx, req.(*SolveLinearSystemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CalculatorService_ServiceDesc is the grpc.ServiceDesc for CalculatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalculatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "calculator.CalculatorService",
+	HandlerType: (*CalculatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sum",
+			Handler:    _CalculatorService_Sum_Handler,
+		},
+		{
+			MethodName: "SquareRoot",
+			Handler:    _CalculatorService_SquareRoot_Handler,
+		},
+		{
+			MethodName: "BigSum",
+			Handler:    _CalculatorService_BigSum_Handler,
+		},
+		{
+			MethodName: "BigSubtract",
+			Handler:    _CalculatorService_BigSubtract_Handler,
+		},
+		{
+			MethodName: "BigMultiply",
+			Handler:    _CalculatorService_BigMultiply_Handler,
+		},
+		{
+			MethodName: "BigDivide",
+			Handler:    _CalculatorService_BigDivide_Handler,
+		},
+		{
+			MethodName: "BigModulo",
+			Handler:    _CalculatorService_BigModulo_Handler,
+		},
+		{
+			MethodName: "BigPower",
+			Handler:    _CalculatorService_BigPower_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalculatorService_Evaluate_Handler,
+		},
+		{
+			MethodName: "DoubleSum",
+			Handler:    _CalculatorService_DoubleSum_Handler,
+		},
+		{
+			MethodName: "DoubleSubtract",
+			Handler:    _CalculatorService_DoubleSubtract_Handler,
+		},
+		{
+			MethodName: "DoubleMultiply",
+			Handler:    _CalculatorService_DoubleMultiply_Handler,
+		},
+		{
+			MethodName: "DoubleDivide",
+			Handler:    _CalculatorService_DoubleDivide_Handler,
+		},
+		{
+			MethodName: "DoublePower",
+			Handler:    _CalculatorService_DoublePower_Handler,
+		},
+		{
+			MethodName: "DoubleSquareRoot",
+			Handler:    _CalculatorService_DoubleSquareRoot_Handler,
+		},
+		{
+			MethodName: "DotProduct",
+			Handler:    _CalculatorService_DotProduct_Handler,
+		},
+		{
+			MethodName: "MatrixMultiply",
+			Handler:    _CalculatorService_MatrixMultiply_Handler,
+		},
+		{
+			MethodName: "Determinant",
+			Handler:    _CalculatorService_Determinant_Handler,
+		},
+		{
+			MethodName: "SolveLinearSystem",
+			Handler:    _CalculatorService_SolveLinearSystem_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PrimeNumberDecomposition",
+			Handler:       _CalculatorService_PrimeNumberDecomposition_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ComputeAverage",
+			Handler:       _CalculatorService_ComputeAverage_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FindMaximum",
+			Handler:       _CalculatorService_FindMaximum_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ComputeStatistics",
+			Handler:       _CalculatorService_ComputeStatistics_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ComputeRunningStatistics",
+			Handler:       _CalculatorService_ComputeRunningStatistics_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AggregateWindows",
+			Handler:       _CalculatorService_AggregateWindows_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "calculator/calculatorpb/calculator.proto",
+}
@@ -0,0 +1,151 @@
+package calculatorserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// maxOperandDigits bounds the size of Big* operands.
+	maxOperandDigits = 10000
+	// maxPowerBits bounds the size of a BigPower result, about 315000
+	// decimal digits.
+	maxPowerBits = 1 << 20
+	// maxCachedDigits bounds the memory a cached result can take.
+	maxCachedDigits = 4096
+)
+
+// parseOperands reads the two decimal operands of a Big* request.
+func parseOperands(req *calculatorpb.BigNumberRequest) (*big.Int, *big.Int, error) {
+	first, err := parseBigInt("first_number", req.GetFirstNumber())
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := parseBigInt("second_number", req.GetSecondNumber())
+	if err != nil {
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+func parseBigInt(field, s string) (*big.Int, error) {
+	if len(s) > maxOperandDigits+1 {
+		return nil, domain.InvalidField("", field, fmt.Sprintf("%s has more than %d digits", field, maxOperandDigits))
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, domain.InvalidField("", field, fmt.Sprintf("%s is not a decimal integer: %q", field, s))
+	}
+	return n, nil
+}
+
+func bigResult(n *big.Int) *calculatorpb.BigNumberResponse {
+	return &calculatorpb.BigNumberResponse{Result: n.String()}
+}
+
+// cacheKey returns the cache key of the named operation on operands. The
+// operands are hashed, so keys stay small however many digits they have.
+func cacheKey(op string, operands ...*big.Int) string {
+	h := sha256.New()
+	for _, n := range operands {
+		fmt.Fprintf(h, "%v,", n)
+	}
+	return op + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// bigCached returns the result of the named operation on first and second,
+// from the cache when possible and from compute otherwise. Only operations
+// that cost much more than a lookup are worth caching: the others are
+// computed in about the time it takes to hash their operands.
+func (s *Server) bigCached(op string, first, second *big.Int, compute func() *big.Int) (*calculatorpb.BigNumberResponse, error) {
+	key := cacheKey(op, first, second)
+	if cached, ok := s.cache.Get(key); ok {
+		return &calculatorpb.BigNumberResponse{Result: cached.(string)}, nil
+	}
+	res := bigResult(compute())
+	if len(res.Result) <= maxCachedDigits {
+		s.cache.Add(key, res.Result)
+	}
+	return res, nil
+}
+
+func (*Server) BigSum(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigSum function was invoked with %v", req)
+	first, second, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	return bigResult(new(big.Int).Add(first, second)), nil
+}
+
+func (*Server) BigSubtract(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigSubtract function was invoked with %v", req)
+	first, second, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	return bigResult(new(big.Int).Sub(first, second)), nil
+}
+
+func (*Server) BigMultiply(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigMultiply function was invoked with %v", req)
+	first, second, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	return bigResult(new(big.Int).Mul(first, second)), nil
+}
+
+func (*Server) BigDivide(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigDivide function was invoked with %v", req)
+	first, second, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	if second.Sign() == 0 {
+		return nil, domain.InvalidField(ReasonDivisionByZero, "second_number", "division by zero")
+	}
+	return bigResult(new(big.Int).Quo(first, second)), nil
+}
+
+func (*Server) BigModulo(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigModulo function was invoked with %v", req)
+	first, second, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	if second.Sign() == 0 {
+		return nil, domain.InvalidField(ReasonDivisionByZero, "second_number", "modulo by zero")
+	}
+	return bigResult(new(big.Int).Mod(first, second)), nil
+}
+
+func (s *Server) BigPower(ctx context.Context, req *calculatorpb.BigNumberRequest) (*calculatorpb.BigNumberResponse, error) {
+	requestid.Logf(ctx, "BigPower function was invoked with %v", req)
+	base, exponent, err := parseOperands(req)
+	if err != nil {
+		return nil, err
+	}
+	if exponent.Sign() < 0 {
+		return nil, domain.InvalidField("", "second_number", "second_number must not be negative")
+	}
+
+	// 0, 1 and -1 stay small whatever the exponent. Anything else has fewer
+	// than exponent*BitLen bits, since |base| < 2^BitLen.
+	abs := new(big.Int).Abs(base)
+	if abs.Cmp(big.NewInt(1)) > 0 {
+		if !exponent.IsInt64() || exponent.Int64() > maxPowerBits/int64(abs.BitLen()) {
+			return nil, domain.Errorf(codes.OutOfRange, ReasonResultOutOfRange, "result would exceed %d bits", maxPowerBits)
+		}
+	}
+	return s.bigCached("exp", base, exponent, func() *big.Int {
+		return new(big.Int).Exp(base, exponent, nil)
+	})
+}
@@ -0,0 +1,96 @@
+package calculatorserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handlerResults reports the result of every streaming handler once it
+// returns.
+func handlerResults() (servertest.Option, <-chan error) {
+	done := make(chan error, 16)
+	opt := servertest.WithServerOptions(grpc.ChainStreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, ss)
+			done <- err
+			return err
+		},
+	))
+	return opt, done
+}
+
+// handlerResult waits for a streaming handler to return.
+func handlerResult(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+		return nil
+	}
+}
+
+// stillServing checks that the server answers a fresh call.
+func stillServing(t *testing.T, c calculatorpb.CalculatorServiceClient) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: 10})
+	if err != nil {
+		t.Fatalf("Sum after a client disconnect: %v", err)
+	}
+	if res.GetSumResult() != 13 {
+		t.Errorf("Sum after a client disconnect: got %d, want 13", res.GetSumResult())
+	}
+}
+
+func TestComputeAverageClientDisconnect(t *testing.T) {
+	opt, done := handlerResults()
+	c := servertest.Calculator(t, opt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.ComputeAverage(ctx)
+	if err != nil {
+		t.Fatalf("ComputeAverage: %v", err)
+	}
+	if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: 4}); err != nil {
+		t.Fatalf("ComputeAverage Send: %v", err)
+	}
+	cancel()
+
+	if err := handlerResult(t, done); status.Code(err) != codes.Canceled {
+		t.Errorf("ComputeAverage handler returned %v, want Canceled", err)
+	}
+	stillServing(t, c)
+}
+
+func TestFindMaximumClientDisconnect(t *testing.T) {
+	opt, done := handlerResults()
+	c := servertest.Calculator(t, opt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.FindMaximum(ctx)
+	if err != nil {
+		t.Fatalf("FindMaximum: %v", err)
+	}
+	if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: 4}); err != nil {
+		t.Fatalf("FindMaximum Send: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("FindMaximum Recv: %v", err)
+	}
+	cancel()
+
+	if err := handlerResult(t, done); status.Code(err) != codes.Canceled {
+		t.Errorf("FindMaximum handler returned %v, want Canceled", err)
+	}
+	stillServing(t, c)
+}
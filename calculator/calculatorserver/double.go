@@ -0,0 +1,88 @@
+package calculatorserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/grpc/codes"
+)
+
+// checkFinite rejects NaN and infinite operands.
+func checkFinite(field string, x float64) error {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return domain.InvalidField("", field, field+" must be a finite number")
+	}
+	return nil
+}
+
+// doubleOperation checks the operands of a Double* request, applies op and
+// checks that the result is a finite number.
+func doubleOperation(req *calculatorpb.DoubleRequest, op func(a, b float64) float64) (*calculatorpb.DoubleResponse, error) {
+	if err := checkFinite("first_number", req.GetFirstNumber()); err != nil {
+		return nil, err
+	}
+	if err := checkFinite("second_number", req.GetSecondNumber()); err != nil {
+		return nil, err
+	}
+	result, err := checkResult(op(req.GetFirstNumber(), req.GetSecondNumber()))
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DoubleResponse{Result: result}, nil
+}
+
+func checkResult(x float64) (float64, error) {
+	if math.IsInf(x, 0) {
+		return 0, domain.Error(codes.OutOfRange, ReasonResultOutOfRange, "result overflows a double")
+	}
+	if math.IsNaN(x) {
+		return 0, domain.Error(codes.InvalidArgument, ReasonNotRealNumber, "result is not a real number")
+	}
+	return x, nil
+}
+
+func (*Server) DoubleSum(ctx context.Context, req *calculatorpb.DoubleRequest) (*calculatorpb.DoubleResponse, error) {
+	requestid.Logf(ctx, "DoubleSum function was invoked with %v", req)
+	return doubleOperation(req, func(a, b float64) float64 { return a + b })
+}
+
+func (*Server) DoubleSubtract(ctx context.Context, req *calculatorpb.DoubleRequest) (*calculatorpb.DoubleResponse, error) {
+	requestid.Logf(ctx, "DoubleSubtract function was invoked with %v", req)
+	return doubleOperation(req, func(a, b float64) float64 { return a - b })
+}
+
+func (*Server) DoubleMultiply(ctx context.Context, req *calculatorpb.DoubleRequest) (*calculatorpb.DoubleResponse, error) {
+	requestid.Logf(ctx, "DoubleMultiply function was invoked with %v", req)
+	return doubleOperation(req, func(a, b float64) float64 { return a * b })
+}
+
+func (*Server) DoubleDivide(ctx context.Context, req *calculatorpb.DoubleRequest) (*calculatorpb.DoubleResponse, error) {
+	requestid.Logf(ctx, "DoubleDivide function was invoked with %v", req)
+	if req.GetSecondNumber() == 0 {
+		return nil, domain.InvalidField(ReasonDivisionByZero, "second_number", "division by zero")
+	}
+	return doubleOperation(req, func(a, b float64) float64 { return a / b })
+}
+
+func (*Server) DoublePower(ctx context.Context, req *calculatorpb.DoubleRequest) (*calculatorpb.DoubleResponse, error) {
+	requestid.Logf(ctx, "DoublePower function was invoked with %v", req)
+	if req.GetFirstNumber() == 0 && req.GetSecondNumber() < 0 {
+		return nil, domain.InvalidField(ReasonDivisionByZero, "second_number", "zero cannot be raised to a negative power")
+	}
+	return doubleOperation(req, math.Pow)
+}
+
+func (*Server) DoubleSquareRoot(ctx context.Context, req *calculatorpb.DoubleSquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	requestid.Logf(ctx, "DoubleSquareRoot function was invoked with %v", req)
+	number := req.GetNumber()
+	if err := checkFinite("number", number); err != nil {
+		return nil, err
+	}
+	if number < 0 {
+		return nil, domain.InvalidField("", "number", fmt.Sprintf("Received a negative number: %v", number))
+	}
+	return &calculatorpb.SquareRootResponse{NumberRoot: math.Sqrt(number)}, nil
+}
@@ -0,0 +1,55 @@
+package calculatorserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/expr"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc/codes"
+)
+
+func (*Server) Evaluate(ctx context.Context, req *calculatorpb.EvaluateRequest) (*calculatorpb.EvaluateResponse, error) {
+	requestid.Logf(ctx, "Evaluate function was invoked with %v", req)
+
+	for name := range req.GetVariables() {
+		if !expr.ValidName(name) {
+			return nil, domain.InvalidField("",
+				fmt.Sprintf("variables[%q]", name),
+				fmt.Sprintf("invalid variable name %q", name),
+			)
+		}
+	}
+
+	e, err := expr.Parse(req.GetExpression())
+	if err != nil {
+		return nil, expressionError(ReasonExpressionSyntax, req.GetExpression(), err)
+	}
+	result, err := e.Eval(req.GetVariables())
+	if err != nil {
+		return nil, expressionError(ReasonExpressionEvaluation, req.GetExpression(), err)
+	}
+
+	return &calculatorpb.EvaluateResponse{Result: result}, nil
+}
+
+// expressionError reports err as INVALID_ARGUMENT with a BadRequest field
+// violation for the expression and an ErrorInfo carrying the position.
+func expressionError(reason, expression string, err error) error {
+	var exprErr *expr.Error
+	if !errors.As(err, &exprErr) {
+		return domain.InvalidField(reason, "expression", err.Error())
+	}
+	return rpcerr.New(codes.InvalidArgument, err.Error(),
+		domain.Info(reason,
+			"position", strconv.Itoa(exprErr.Pos),
+			"message", exprErr.Msg,
+			"expression", expression,
+		),
+		rpcerr.FieldViolation("expression", exprErr.Error()),
+	)
+}
@@ -0,0 +1,151 @@
+package calculatorserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/linalg"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/grpc/codes"
+)
+
+// maxDimension bounds the rows, columns and vector lengths accepted by the
+// linear algebra RPCs, which take up to cubic time in it.
+const maxDimension = 500
+
+func vectorValues(field string, v *calculatorpb.Vector) ([]float64, error) {
+	values := v.GetValues()
+	if len(values) > maxDimension {
+		return nil, domain.InvalidField("", field, fmt.Sprintf("%s has more than %d values", field, maxDimension))
+	}
+	for i, x := range values {
+		if err := checkFinite(fmt.Sprintf("%s.values[%d]", field, i), x); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// matrixRows converts m, checking that it is rectangular and within limits.
+func matrixRows(field string, m *calculatorpb.Matrix) ([][]float64, error) {
+	if len(m.GetRows()) > maxDimension {
+		return nil, domain.InvalidField("", field, fmt.Sprintf("%s has more than %d rows", field, maxDimension))
+	}
+	rows := make([][]float64, len(m.GetRows()))
+	for i, row := range m.GetRows() {
+		values, err := vectorValues(fmt.Sprintf("%s.rows[%d]", field, i), row)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = values
+	}
+	if _, _, err := linalg.Dims(rows); err != nil {
+		return nil, domain.InvalidField("", field, fmt.Sprintf("%s is not rectangular: %v", field, err))
+	}
+	return rows, nil
+}
+
+func toMatrix(rows [][]float64) (*calculatorpb.Matrix, error) {
+	m := &calculatorpb.Matrix{}
+	for _, row := range rows {
+		for _, x := range row {
+			if _, err := checkResult(x); err != nil {
+				return nil, err
+			}
+		}
+		m.Rows = append(m.Rows, &calculatorpb.Vector{Values: row})
+	}
+	return m, nil
+}
+
+func (*Server) DotProduct(ctx context.Context, req *calculatorpb.DotProductRequest) (*calculatorpb.DotProductResponse, error) {
+	requestid.Logf(ctx, "DotProduct function was invoked")
+	a, err := vectorValues("first_vector", req.GetFirstVector())
+	if err != nil {
+		return nil, err
+	}
+	b, err := vectorValues("second_vector", req.GetSecondVector())
+	if err != nil {
+		return nil, err
+	}
+	dot, err := linalg.Dot(a, b)
+	if err != nil {
+		return nil, domain.InvalidField("", "second_vector", err.Error())
+	}
+	result, err := checkResult(dot)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DotProductResponse{Result: result}, nil
+}
+
+func (*Server) MatrixMultiply(ctx context.Context, req *calculatorpb.MatrixMultiplyRequest) (*calculatorpb.MatrixMultiplyResponse, error) {
+	requestid.Logf(ctx, "MatrixMultiply function was invoked")
+	a, err := matrixRows("first_matrix", req.GetFirstMatrix())
+	if err != nil {
+		return nil, err
+	}
+	b, err := matrixRows("second_matrix", req.GetSecondMatrix())
+	if err != nil {
+		return nil, err
+	}
+	product, err := linalg.Multiply(a, b)
+	if err != nil {
+		return nil, domain.InvalidField("", "second_matrix", err.Error())
+	}
+	result, err := toMatrix(product)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.MatrixMultiplyResponse{Result: result}, nil
+}
+
+func (*Server) Determinant(ctx context.Context, req *calculatorpb.DeterminantRequest) (*calculatorpb.DeterminantResponse, error) {
+	requestid.Logf(ctx, "Determinant function was invoked")
+	m, err := matrixRows("matrix", req.GetMatrix())
+	if err != nil {
+		return nil, err
+	}
+	det, err := linalg.Determinant(m)
+	if err != nil {
+		return nil, domain.InvalidField("", "matrix", err.Error())
+	}
+	result, err := checkResult(det)
+	if err != nil {
+		return nil, err
+	}
+	return &calculatorpb.DeterminantResponse{Determinant: result}, nil
+}
+
+func (*Server) SolveLinearSystem(ctx context.Context, req *calculatorpb.SolveLinearSystemRequest) (*calculatorpb.SolveLinearSystemResponse, error) {
+	requestid.Logf(ctx, "SolveLinearSystem function was invoked")
+	m, err := matrixRows("matrix", req.GetMatrix())
+	if err != nil {
+		return nil, err
+	}
+	if rows, cols, _ := linalg.Dims(m); rows != cols {
+		return nil, domain.InvalidField("", "matrix", fmt.Sprintf("matrix is %dx%d, not square", rows, cols))
+	}
+	v, err := vectorValues("vector", req.GetVector())
+	if err != nil {
+		return nil, err
+	}
+	if len(v) != len(m) {
+		return nil, domain.InvalidField("", "vector", fmt.Sprintf("vector has %d values for %d equations", len(v), len(m)))
+	}
+
+	x, err := linalg.Solve(m, v)
+	if err == linalg.ErrSingular {
+		return nil, domain.Error(codes.FailedPrecondition, ReasonSingularMatrix, "matrix is singular, the system has no unique solution")
+	}
+	if err != nil {
+		return nil, domain.InvalidField("", "matrix", err.Error())
+	}
+	for _, xi := range x {
+		if _, err := checkResult(xi); err != nil {
+			return nil, err
+		}
+	}
+	return &calculatorpb.SolveLinearSystemResponse{Solution: &calculatorpb.Vector{Values: x}}, nil
+}
@@ -0,0 +1,218 @@
+// Package calculatorserver implements the calculator service. Registering
+// it is up to the caller, along with the interceptors and options of the
+// server.
+package calculatorserver
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/factor"
+	"github.com/pandadragoon/grpc-go-course/internal/cache"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+)
+
+// domain is the ErrorInfo domain of calculator errors.
+const domain rpcerr.Domain = "calculator.CalculatorService"
+
+// Reasons of calculator errors, besides rpcerr.ReasonInvalidField.
+const (
+	ReasonExpressionSyntax     = "EXPRESSION_SYNTAX"
+	ReasonExpressionEvaluation = "EXPRESSION_EVALUATION"
+	ReasonDivisionByZero       = "DIVISION_BY_ZERO"
+	// ReasonResultOutOfRange is an OUT_OF_RANGE result, such as a Sum that
+	// does not fit in 32 bits.
+	ReasonResultOutOfRange = "RESULT_OUT_OF_RANGE"
+	// ReasonNotRealNumber is a result that is not a real number.
+	ReasonNotRealNumber  = "NOT_REAL_NUMBER"
+	ReasonSingularMatrix = "SINGULAR_MATRIX"
+	// ReasonStreamOrder is a client stream whose messages come in the wrong
+	// order, such as options after numbers.
+	ReasonStreamOrder = "STREAM_ORDER"
+	// ReasonEmptyStream is a client stream that ended without numbers.
+	ReasonEmptyStream = "EMPTY_STREAM"
+	// ReasonStreamTooLong is a client stream with more numbers than are
+	// accepted.
+	ReasonStreamTooLong = "STREAM_TOO_LONG"
+)
+
+// Server implements calculatorpb.CalculatorServiceServer.
+type Server struct {
+	// Methods added to the proto answer Unimplemented until they are
+	// implemented here.
+	calculatorpb.UnimplementedCalculatorServiceServer
+
+	// cache holds factorizations and BigPower results; nil disables
+	// caching.
+	cache *cache.Cache
+}
+
+// New returns a calculator service that caches results in c, which may be
+// nil for no caching.
+func New(c *cache.Cache) *Server {
+	return &Server{cache: c}
+}
+
+func (*Server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
+	requestid.Logf(ctx, "Sum function was invoked with %v", req)
+	first_number := req.GetFirstNumber()
+	second_number := req.GetSecondNumber()
+
+	sum_result := int64(first_number) + int64(second_number)
+	if sum_result > math.MaxInt32 || sum_result < math.MinInt32 {
+		return nil, domain.Errorf(
+			codes.OutOfRange, ReasonResultOutOfRange,
+			"%d + %d does not fit in a 32-bit sum_result, use BigSum instead",
+			first_number, second_number,
+		)
+	}
+
+	res := &calculatorpb.SumResponse{
+		SumResult: int32(sum_result),
+	}
+
+	return res, nil
+}
+
+func (s *Server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
+	ctx := stream.Context()
+	requestid.Logf(ctx, "PrimeNumberDecomposition function was invoked with %v", req)
+
+	var number *big.Int
+	if req.GetBigNumber() != "" {
+		n, err := parseBigInt("big_number", req.GetBigNumber())
+		if err != nil {
+			return err
+		}
+		if n.Sign() <= 0 {
+			return domain.InvalidField("", "big_number", fmt.Sprintf("big_number must be positive, got %v", n))
+		}
+		number = n
+	} else {
+		if req.GetNumber() <= 0 {
+			return domain.InvalidField("", "number", fmt.Sprintf("number must be positive, got %d", req.GetNumber()))
+		}
+		number = big.NewInt(req.GetNumber())
+	}
+
+	// Keyed by value, so number and big_number share entries.
+	key := cacheKey("factor", number)
+	if cached, ok := s.cache.Get(key); ok {
+		for _, res := range cached.([]*calculatorpb.PrimeNumberDecompositionResponse) {
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var factors []*calculatorpb.PrimeNumberDecompositionResponse
+	send := func(prime *big.Int, multiplicity int) error {
+		res := &calculatorpb.PrimeNumberDecompositionResponse{
+			Multiplicity:   int32(multiplicity),
+			BigPrimeFactor: prime.String(),
+		}
+		if prime.IsInt64() {
+			res.PrimeFactor = prime.Int64()
+		}
+		factors = append(factors, res)
+		return stream.Send(res)
+	}
+
+	var err error
+	if number.IsUint64() {
+		err = factor.Factor(ctx, number.Uint64(), func(prime uint64, multiplicity int) error {
+			return send(new(big.Int).SetUint64(prime), multiplicity)
+		})
+	} else {
+		err = factor.FactorBig(ctx, number, send)
+	}
+
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+	if err != nil {
+		return err
+	}
+	s.cache.Add(key, factors)
+	return nil
+}
+
+func (*Server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
+	requestid.Logf(stream.Context(), "ComputeAverage function was invoked")
+	sum := float64(0)
+	count := float64(0)
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			if count == 0 {
+				return domain.Error(codes.InvalidArgument, ReasonEmptyStream, "no numbers received")
+			}
+			err := stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
+				Average: sum / count,
+			})
+			if err != nil {
+				return err
+			}
+			return nil
+		}
+		if err != nil {
+			requestid.Logf(stream.Context(), "ComputeAverage: error receiving client stream: %v", err)
+			return err
+		}
+		number := float64(req.GetNumber())
+		count++
+		sum += number
+	}
+}
+
+func (*Server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
+	maximum := int32(0)
+	seen := false
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			requestid.Logf(stream.Context(), "FindMaximum: error receiving client stream: %v", err)
+			return err
+		}
+
+		if !seen || maximum < req.GetNumber() {
+			maximum = req.GetNumber()
+			seen = true
+			err = stream.Send(&calculatorpb.FindMaximumResponse{
+				Maximum: maximum,
+			})
+			if err != nil {
+				requestid.Logf(stream.Context(), "FindMaximum: error sending response to client: %v", err)
+				return err
+			}
+		}
+	}
+}
+
+func (*Server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+	requestid.Logf(ctx, "Received SquareRoot RPC")
+	number := req.GetNumber()
+	if number < 0 {
+		return nil, domain.InvalidField("", "number", fmt.Sprintf("Received a negative number: %d", number))
+	}
+	numberRoot := math.Sqrt(float64(number))
+
+	res := calculatorpb.SquareRootResponse{
+		NumberRoot: numberRoot,
+	}
+
+	return &res, nil
+}
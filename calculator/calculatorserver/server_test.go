@@ -0,0 +1,1105 @@
+package calculatorserver_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorserver"
+	"github.com/pandadragoon/grpc-go-course/internal/cache"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func vector(values ...float64) *calculatorpb.Vector {
+	return &calculatorpb.Vector{Values: values}
+}
+
+func matrix(rows ...[]float64) *calculatorpb.Matrix {
+	m := &calculatorpb.Matrix{}
+	for _, row := range rows {
+		m.Rows = append(m.Rows, vector(row...))
+	}
+	return m
+}
+
+// checkStatus checks that err has code want and, when field is set, a
+// BadRequest detail about field.
+func checkStatus(t *testing.T, err error, want codes.Code, field string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != want {
+		t.Fatalf("got %v, want %v", err, want)
+	}
+	if field == "" {
+		return
+	}
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				if v.GetField() == field {
+					return
+				}
+			}
+		}
+	}
+	t.Errorf("%v has no BadRequest violation for %s", err, field)
+}
+
+func TestUnary(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		name string
+		call func(context.Context) (proto.Message, error)
+		want proto.Message
+		// wantCode and wantField describe the expected error, if any.
+		wantCode  codes.Code
+		wantField string
+	}{
+		{
+			name: "Sum",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: 10})
+			},
+			want: &calculatorpb.SumResponse{SumResult: 13},
+		},
+		{
+			name: "Sum overflow",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: math.MaxInt32, SecondNumber: 1})
+			},
+			wantCode: codes.OutOfRange,
+		},
+		{
+			name: "SquareRoot",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.SquareRoot(ctx, &calculatorpb.SquareRootRequest{Number: 16})
+			},
+			want: &calculatorpb.SquareRootResponse{NumberRoot: 4},
+		},
+		{
+			name: "SquareRoot negative",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.SquareRoot(ctx, &calculatorpb.SquareRootRequest{Number: -1})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigSum",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigSum(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "99999999999999999999", SecondNumber: "1"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "100000000000000000000"},
+		},
+		{
+			name: "BigSum not a number",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigSum(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "1.5", SecondNumber: "1"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigSum too many digits",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigSum(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "1", SecondNumber: strings.Repeat("9", 10002)})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigSubtract",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigSubtract(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "1", SecondNumber: "100000000000000000000"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "-99999999999999999999"},
+		},
+		{
+			name: "BigMultiply",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigMultiply(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "4294967296", SecondNumber: "4294967296"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "18446744073709551616"},
+		},
+		{
+			name: "BigDivide",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigDivide(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "-7", SecondNumber: "2"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "-3"},
+		},
+		{
+			name: "BigDivide by zero",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigDivide(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "1", SecondNumber: "0"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigModulo",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigModulo(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "-7", SecondNumber: "3"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "2"},
+		},
+		{
+			name: "BigModulo by zero",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigModulo(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "1", SecondNumber: "0"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigPower",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigPower(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "2", SecondNumber: "100"})
+			},
+			want: &calculatorpb.BigNumberResponse{Result: "1267650600228229401496703205376"},
+		},
+		{
+			name: "BigPower negative exponent",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigPower(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "2", SecondNumber: "-1"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "BigPower too large",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.BigPower(ctx, &calculatorpb.BigNumberRequest{FirstNumber: "2", SecondNumber: "100000000"})
+			},
+			wantCode: codes.OutOfRange,
+		},
+		{
+			name: "Evaluate",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "2 * (x + 1)", Variables: map[string]float64{"x": 3}})
+			},
+			want: &calculatorpb.EvaluateResponse{Result: 8},
+		},
+		{
+			name: "Evaluate syntax error",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "2 * (1"})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "expression",
+		},
+		{
+			name: "Evaluate unknown variable",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "y + 1"})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "expression",
+		},
+		{
+			name: "Evaluate invalid variable name",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Evaluate(ctx, &calculatorpb.EvaluateRequest{Expression: "1", Variables: map[string]float64{"1x": 1}})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: `variables["1x"]`,
+		},
+		{
+			name: "DoubleSum",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSum(ctx, &calculatorpb.DoubleRequest{FirstNumber: 1.5, SecondNumber: 2.25})
+			},
+			want: &calculatorpb.DoubleResponse{Result: 3.75},
+		},
+		{
+			name: "DoubleSum not finite",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSum(ctx, &calculatorpb.DoubleRequest{FirstNumber: math.NaN(), SecondNumber: 1})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "first_number",
+		},
+		{
+			name: "DoubleSum overflow",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSum(ctx, &calculatorpb.DoubleRequest{FirstNumber: math.MaxFloat64, SecondNumber: math.MaxFloat64})
+			},
+			wantCode: codes.OutOfRange,
+		},
+		{
+			name: "DoubleSubtract",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSubtract(ctx, &calculatorpb.DoubleRequest{FirstNumber: 1.5, SecondNumber: 2.25})
+			},
+			want: &calculatorpb.DoubleResponse{Result: -0.75},
+		},
+		{
+			name: "DoubleMultiply",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleMultiply(ctx, &calculatorpb.DoubleRequest{FirstNumber: 1.5, SecondNumber: -4})
+			},
+			want: &calculatorpb.DoubleResponse{Result: -6},
+		},
+		{
+			name: "DoubleDivide",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleDivide(ctx, &calculatorpb.DoubleRequest{FirstNumber: 1, SecondNumber: 4})
+			},
+			want: &calculatorpb.DoubleResponse{Result: 0.25},
+		},
+		{
+			name: "DoubleDivide by zero",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleDivide(ctx, &calculatorpb.DoubleRequest{FirstNumber: 1, SecondNumber: 0})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "second_number",
+		},
+		{
+			name: "DoublePower",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoublePower(ctx, &calculatorpb.DoubleRequest{FirstNumber: 2, SecondNumber: -2})
+			},
+			want: &calculatorpb.DoubleResponse{Result: 0.25},
+		},
+		{
+			name: "DoublePower zero to a negative power",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoublePower(ctx, &calculatorpb.DoubleRequest{FirstNumber: 0, SecondNumber: -1})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "second_number",
+		},
+		{
+			name: "DoublePower not real",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoublePower(ctx, &calculatorpb.DoubleRequest{FirstNumber: -8, SecondNumber: 0.5})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "DoubleSquareRoot",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSquareRoot(ctx, &calculatorpb.DoubleSquareRootRequest{Number: 2.25})
+			},
+			want: &calculatorpb.SquareRootResponse{NumberRoot: 1.5},
+		},
+		{
+			name: "DoubleSquareRoot negative",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DoubleSquareRoot(ctx, &calculatorpb.DoubleSquareRootRequest{Number: -2.25})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "DotProduct",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DotProduct(ctx, &calculatorpb.DotProductRequest{FirstVector: vector(1, 2, 3), SecondVector: vector(4, -5, 6)})
+			},
+			want: &calculatorpb.DotProductResponse{Result: 12},
+		},
+		{
+			name: "DotProduct length mismatch",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DotProduct(ctx, &calculatorpb.DotProductRequest{FirstVector: vector(1, 2), SecondVector: vector(1)})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "second_vector",
+		},
+		{
+			name: "DotProduct not finite",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.DotProduct(ctx, &calculatorpb.DotProductRequest{FirstVector: vector(1, math.Inf(1)), SecondVector: vector(1, 2)})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "first_vector.values[1]",
+		},
+		{
+			name: "MatrixMultiply",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{
+					FirstMatrix:  matrix([]float64{1, 2}, []float64{3, 4}),
+					SecondMatrix: matrix([]float64{5}, []float64{6}),
+				})
+			},
+			want: &calculatorpb.MatrixMultiplyResponse{Result: matrix([]float64{17}, []float64{39})},
+		},
+		{
+			name: "MatrixMultiply dimension mismatch",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{
+					FirstMatrix:  matrix([]float64{1, 2}),
+					SecondMatrix: matrix([]float64{1, 2}),
+				})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "second_matrix",
+		},
+		{
+			name: "MatrixMultiply ragged",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{
+					FirstMatrix:  matrix([]float64{1, 2}, []float64{3}),
+					SecondMatrix: matrix([]float64{1}, []float64{2}),
+				})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "first_matrix",
+		},
+		{
+			name: "Determinant",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Determinant(ctx, &calculatorpb.DeterminantRequest{Matrix: matrix([]float64{0, 2}, []float64{3, 0})})
+			},
+			want: &calculatorpb.DeterminantResponse{Determinant: -6},
+		},
+		{
+			name: "Determinant not square",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.Determinant(ctx, &calculatorpb.DeterminantRequest{Matrix: matrix([]float64{1, 2})})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "matrix",
+		},
+		{
+			name: "SolveLinearSystem",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{
+					Matrix: matrix([]float64{2, 0}, []float64{0, 4}),
+					Vector: vector(2, 8),
+				})
+			},
+			want: &calculatorpb.SolveLinearSystemResponse{Solution: vector(1, 2)},
+		},
+		{
+			name: "SolveLinearSystem singular",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{
+					Matrix: matrix([]float64{1, 2}, []float64{2, 4}),
+					Vector: vector(1, 2),
+				})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "SolveLinearSystem wrong vector length",
+			call: func(ctx context.Context) (proto.Message, error) {
+				return c.SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{
+					Matrix: matrix([]float64{1, 0}, []float64{0, 1}),
+					Vector: vector(1),
+				})
+			},
+			wantCode:  codes.InvalidArgument,
+			wantField: "vector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call(testContext(t))
+			if tt.wantCode != codes.OK {
+				checkStatus(t, err, tt.wantCode, tt.wantField)
+				return
+			}
+			if err != nil {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBigPowerLimit checks the bound on BigPower results, 1<<20 bits, just
+// below and above it: |base| < 2^BitLen, so 3, which is 2 bits long, may be
+// raised to at most 1<<19.
+func TestBigPowerLimit(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		base, exponent string
+		wantBits       int
+		wantCode       codes.Code
+	}{
+		{base: "3", exponent: "524288", wantBits: 830977},
+		{base: "3", exponent: "524289", wantCode: codes.OutOfRange},
+		{base: "-2", exponent: "524288", wantBits: 524289},
+		{base: "-2", exponent: "524289", wantCode: codes.OutOfRange},
+		{base: "1024", exponent: "95325", wantBits: 953251},
+		{base: "1024", exponent: "95326", wantCode: codes.OutOfRange},
+		// 0, 1 and -1 stay small whatever the exponent.
+		{base: "-1", exponent: "1" + strings.Repeat("0", 100), wantBits: 1},
+		{base: "0", exponent: "1" + strings.Repeat("0", 100), wantBits: 0},
+	}
+	for _, tt := range tests {
+		res, err := c.BigPower(testContext(t), &calculatorpb.BigNumberRequest{FirstNumber: tt.base, SecondNumber: tt.exponent})
+		if tt.wantCode != codes.OK {
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("%s^%s: got %v, want %v", tt.base, tt.exponent, err, tt.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s^%s: %v", tt.base, tt.exponent, err)
+			continue
+		}
+		n, ok := new(big.Int).SetString(res.GetResult(), 10)
+		if !ok || n.BitLen() != tt.wantBits {
+			t.Errorf("%s^%s: got a %d bit result, want %d bits", tt.base, tt.exponent, n.BitLen(), tt.wantBits)
+		}
+	}
+}
+
+// TestCache checks that only BigPower results and factorizations are
+// cached, whatever the size of their operands.
+func TestCache(t *testing.T) {
+	results := cache.New(16, time.Minute)
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New(results))
+	})
+	c := calculatorpb.NewCalculatorServiceClient(conn)
+	ctx := testContext(t)
+
+	large := strings.Repeat("9", 10000)
+	for i := 0; i < 2; i++ {
+		for _, req := range []*calculatorpb.BigNumberRequest{
+			{FirstNumber: "3", SecondNumber: "100"},
+			{FirstNumber: large, SecondNumber: "1"},
+		} {
+			if _, err := c.BigPower(ctx, req); err != nil {
+				t.Fatalf("BigPower: %v", err)
+			}
+		}
+		if _, err := c.BigMultiply(ctx, &calculatorpb.BigNumberRequest{FirstNumber: large, SecondNumber: large}); err != nil {
+			t.Fatalf("BigMultiply: %v", err)
+		}
+		if _, err := c.BigDivide(ctx, &calculatorpb.BigNumberRequest{FirstNumber: large, SecondNumber: "7"}); err != nil {
+			t.Fatalf("BigDivide: %v", err)
+		}
+		if _, err := c.BigModulo(ctx, &calculatorpb.BigNumberRequest{FirstNumber: large, SecondNumber: "7"}); err != nil {
+			t.Fatalf("BigModulo: %v", err)
+		}
+		stream, err := c.PrimeNumberDecomposition(ctx, &calculatorpb.PrimeNumberDecompositionRequest{Number: 120})
+		if err != nil {
+			t.Fatalf("PrimeNumberDecomposition: %v", err)
+		}
+		for {
+			if _, err := stream.Recv(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("PrimeNumberDecomposition: %v", err)
+			}
+		}
+	}
+
+	// The power of the large number has too many digits to be kept.
+	if s := results.Stats(); s.Entries != 2 || s.Hits != 2 || s.Misses != 4 {
+		t.Errorf("cache stats %+v, want 2 entries, 2 hits and 4 misses", s)
+	}
+}
+
+func TestPrimeNumberDecomposition(t *testing.T) {
+	c := servertest.Calculator(t)
+	factor := func(prime int64, multiplicity int32) *calculatorpb.PrimeNumberDecompositionResponse {
+		return &calculatorpb.PrimeNumberDecompositionResponse{
+			PrimeFactor:    prime,
+			Multiplicity:   multiplicity,
+			BigPrimeFactor: strconv.FormatInt(prime, 10),
+		}
+	}
+	tests := []struct {
+		name     string
+		req      *calculatorpb.PrimeNumberDecompositionRequest
+		want     []*calculatorpb.PrimeNumberDecompositionResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "number",
+			req:  &calculatorpb.PrimeNumberDecompositionRequest{Number: 120},
+			want: []*calculatorpb.PrimeNumberDecompositionResponse{factor(2, 3), factor(3, 1), factor(5, 1)},
+		},
+		{
+			name: "prime",
+			req:  &calculatorpb.PrimeNumberDecompositionRequest{Number: 1000003},
+			want: []*calculatorpb.PrimeNumberDecompositionResponse{factor(1000003, 1)},
+		},
+		{
+			name: "one",
+			req:  &calculatorpb.PrimeNumberDecompositionRequest{Number: 1},
+		},
+		{
+			name: "big number",
+			// 2^64 + 1
+			req:  &calculatorpb.PrimeNumberDecompositionRequest{BigNumber: "18446744073709551617"},
+			want: []*calculatorpb.PrimeNumberDecompositionResponse{factor(274177, 1), factor(67280421310721, 1)},
+		},
+		{
+			name:     "zero",
+			req:      &calculatorpb.PrimeNumberDecompositionRequest{Number: 0},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative big number",
+			req:      &calculatorpb.PrimeNumberDecompositionRequest{BigNumber: "-5"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "big number not a number",
+			req:      &calculatorpb.PrimeNumberDecompositionRequest{BigNumber: "twelve"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.PrimeNumberDecomposition(testContext(t), tt.req)
+			if err != nil {
+				t.Fatalf("PrimeNumberDecomposition: %v", err)
+			}
+			var got []*calculatorpb.PrimeNumberDecompositionResponse
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					checkStatus(t, err, tt.wantCode, "")
+					return
+				}
+				got = append(got, res)
+			}
+			if tt.wantCode != codes.OK {
+				t.Fatalf("succeeded, want %v", tt.wantCode)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("factor %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeAverage(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		name     string
+		numbers  []int32
+		want     float64
+		wantCode codes.Code
+	}{
+		{name: "one", numbers: []int32{7}, want: 7},
+		{name: "several", numbers: []int32{1, 2, 3, 4}, want: 2.5},
+		{name: "negative", numbers: []int32{-3, 1}, want: -1},
+		{name: "none", wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.ComputeAverage(testContext(t))
+			if err != nil {
+				t.Fatalf("ComputeAverage: %v", err)
+			}
+			for _, n := range tt.numbers {
+				if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: n}); err != nil {
+					t.Fatalf("ComputeAverage Send: %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if tt.wantCode != codes.OK {
+				checkStatus(t, err, tt.wantCode, "")
+				return
+			}
+			if err != nil {
+				t.Fatalf("ComputeAverage: %v", err)
+			}
+			if res.GetAverage() != tt.want {
+				t.Errorf("ComputeAverage: got %v, want %v", res.GetAverage(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMaximum(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		name    string
+		numbers []int32
+		want    []int32
+	}{
+		{name: "none"},
+		{name: "increasing and not", numbers: []int32{1, 5, 3, 6, 2}, want: []int32{1, 5, 6}},
+		{name: "negative first", numbers: []int32{-3, -5, -1}, want: []int32{-3, -1}},
+		{name: "zero first", numbers: []int32{0, -1}, want: []int32{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.FindMaximum(testContext(t))
+			if err != nil {
+				t.Fatalf("FindMaximum: %v", err)
+			}
+			for _, n := range tt.numbers {
+				if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: n}); err != nil {
+					t.Fatalf("FindMaximum Send: %v", err)
+				}
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("FindMaximum CloseSend: %v", err)
+			}
+			var got []int32
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("FindMaximum Recv: %v", err)
+				}
+				got = append(got, res.GetMaximum())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindMaximum: got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("FindMaximum: got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func statisticsRequests(options *calculatorpb.StatisticsOptions, numbers ...float64) []*calculatorpb.ComputeStatisticsRequest {
+	var reqs []*calculatorpb.ComputeStatisticsRequest
+	if options != nil {
+		reqs = append(reqs, &calculatorpb.ComputeStatisticsRequest{
+			Request: &calculatorpb.ComputeStatisticsRequest_Options{Options: options},
+		})
+	}
+	for _, x := range numbers {
+		reqs = append(reqs, &calculatorpb.ComputeStatisticsRequest{
+			Request: &calculatorpb.ComputeStatisticsRequest_Number{Number: x},
+		})
+	}
+	return reqs
+}
+
+func TestComputeStatistics(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		name     string
+		reqs     []*calculatorpb.ComputeStatisticsRequest
+		want     *calculatorpb.ComputeStatisticsResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "without options",
+			reqs: statisticsRequests(nil, 4, 2),
+			want: &calculatorpb.ComputeStatisticsResponse{Count: 2, Min: 2, Max: 4, Mean: 3, Variance: 1, Stddev: 1, Median: 3},
+		},
+		{
+			name: "percentiles",
+			reqs: statisticsRequests(&calculatorpb.StatisticsOptions{Percentiles: []float64{25, 100}}, 5, 1, 4, 2, 3),
+			want: &calculatorpb.ComputeStatisticsResponse{
+				Count: 5, Min: 1, Max: 5, Mean: 3, Variance: 2, Stddev: math.Sqrt(2), Median: 3,
+				Percentiles: []*calculatorpb.Percentile{{Percentile: 25, Value: 2}, {Percentile: 100, Value: 5}},
+			},
+		},
+		{
+			name:     "no numbers",
+			reqs:     statisticsRequests(&calculatorpb.StatisticsOptions{}),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "percentile out of range",
+			reqs:     statisticsRequests(&calculatorpb.StatisticsOptions{Percentiles: []float64{101}}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "options after numbers",
+			reqs:     append(statisticsRequests(nil, 1), statisticsRequests(&calculatorpb.StatisticsOptions{})...),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not finite",
+			reqs:     statisticsRequests(nil, 1, math.Inf(-1)),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty request",
+			reqs:     []*calculatorpb.ComputeStatisticsRequest{{}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.ComputeStatistics(testContext(t))
+			if err != nil {
+				t.Fatalf("ComputeStatistics: %v", err)
+			}
+			for _, req := range tt.reqs {
+				// The server may already have given up on an invalid
+				// request; CloseAndRecv reports why.
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if tt.wantCode != codes.OK {
+				checkStatus(t, err, tt.wantCode, "")
+				return
+			}
+			if err != nil {
+				t.Fatalf("ComputeStatistics: %v", err)
+			}
+			if !proto.Equal(res, tt.want) {
+				t.Errorf("ComputeStatistics: got %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeRunningStatistics(t *testing.T) {
+	c := servertest.Calculator(t)
+	tests := []struct {
+		name      string
+		reqs      []*calculatorpb.ComputeStatisticsRequest
+		wantCount []int64
+		wantMax   []float64
+		wantCode  codes.Code
+	}{
+		{
+			name:      "every number",
+			reqs:      statisticsRequests(nil, 1, 3, 2),
+			wantCount: []int64{1, 2, 3},
+			wantMax:   []float64{1, 3, 3},
+		},
+		{
+			name:      "batches with a partial one",
+			reqs:      statisticsRequests(&calculatorpb.StatisticsOptions{EmitEvery: 2}, 1, 2, 3, 4, 5),
+			wantCount: []int64{2, 4, 5},
+			wantMax:   []float64{2, 4, 5},
+		},
+		{
+			name: "no numbers",
+			reqs: statisticsRequests(&calculatorpb.StatisticsOptions{EmitEvery: 2}),
+		},
+		{
+			name:     "negative emit_every",
+			reqs:     statisticsRequests(&calculatorpb.StatisticsOptions{EmitEvery: -1}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.ComputeRunningStatistics(testContext(t))
+			if err != nil {
+				t.Fatalf("ComputeRunningStatistics: %v", err)
+			}
+			for _, req := range tt.reqs {
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			stream.CloseSend()
+
+			var count []int64
+			var max []float64
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					checkStatus(t, err, tt.wantCode, "")
+					return
+				}
+				count = append(count, res.GetCount())
+				max = append(max, res.GetMax())
+			}
+			if tt.wantCode != codes.OK {
+				t.Fatalf("ComputeRunningStatistics succeeded, want %v", tt.wantCode)
+			}
+			if len(count) != len(tt.wantCount) {
+				t.Fatalf("ComputeRunningStatistics: got counts %v, want %v", count, tt.wantCount)
+			}
+			for i := range count {
+				if count[i] != tt.wantCount[i] || max[i] != tt.wantMax[i] {
+					t.Fatalf("ComputeRunningStatistics: got counts %v and maxima %v, want %v and %v", count, max, tt.wantCount, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+// TestComputeRunningStatisticsLimit checks that a stream summarized after
+// every number is cut off well before maxStatisticsCount, since each summary
+// sorts in the numbers since the last one.
+func TestComputeRunningStatisticsLimit(t *testing.T) {
+	c := servertest.Calculator(t)
+	stream, err := c.ComputeRunningStatistics(testContext(t))
+	if err != nil {
+		t.Fatalf("ComputeRunningStatistics: %v", err)
+	}
+	go func() {
+		for i := 0; i < 1<<16; i++ {
+			if err := stream.Send(statisticsRequests(nil, float64(i))[0]); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	var last int64
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			checkStatus(t, err, codes.ResourceExhausted, "")
+			break
+		}
+		last = res.GetCount()
+	}
+	if want := int64(46340); last != want {
+		t.Errorf("last summary of %d numbers, want %d", last, want)
+	}
+}
+
+func windowRequests(options *calculatorpb.WindowOptions, numbers ...float64) []*calculatorpb.AggregateWindowsRequest {
+	var reqs []*calculatorpb.AggregateWindowsRequest
+	if options != nil {
+		reqs = append(reqs, &calculatorpb.AggregateWindowsRequest{
+			Request: &calculatorpb.AggregateWindowsRequest_Options{Options: options},
+		})
+	}
+	for _, x := range numbers {
+		reqs = append(reqs, &calculatorpb.AggregateWindowsRequest{
+			Request: &calculatorpb.AggregateWindowsRequest_Number{Number: x},
+		})
+	}
+	return reqs
+}
+
+func TestAggregateWindows(t *testing.T) {
+	c := servertest.Calculator(t)
+	window := func(result float64, count, first, last int64) *calculatorpb.AggregateWindowsResponse {
+		return &calculatorpb.AggregateWindowsResponse{Result: result, Count: count, FirstIndex: first, LastIndex: last}
+	}
+	tests := []struct {
+		name     string
+		reqs     []*calculatorpb.AggregateWindowsRequest
+		want     []*calculatorpb.AggregateWindowsResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "tumbling sum",
+			reqs: windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_SUM, Size: 3}, 1, 2, 3, 4, 5, 6, 7),
+			want: []*calculatorpb.AggregateWindowsResponse{window(6, 3, 0, 2), window(15, 3, 3, 5), window(7, 1, 6, 6)},
+		},
+		{
+			name: "sliding max",
+			reqs: windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_MAX, Size: 2, Slide: 1}, 3, 1, 2),
+			want: []*calculatorpb.AggregateWindowsResponse{window(3, 2, 0, 1), window(2, 2, 1, 2), window(2, 1, 2, 2)},
+		},
+		{
+			name: "mean",
+			reqs: windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_MEAN, Size: 4}, 1, 2, 3, 4),
+			want: []*calculatorpb.AggregateWindowsResponse{window(2.5, 4, 0, 3)},
+		},
+		{
+			name:     "no options",
+			reqs:     windowRequests(nil, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty stream",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unspecified aggregation",
+			reqs:     windowRequests(&calculatorpb.WindowOptions{Size: 3}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "no size",
+			reqs:     windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_MIN}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "size and duration",
+			reqs: windowRequests(&calculatorpb.WindowOptions{
+				Aggregation: calculatorpb.Aggregation_AGGREGATION_MIN, Size: 3, Duration: durationpb.New(time.Second),
+			}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "duration too short",
+			reqs: windowRequests(&calculatorpb.WindowOptions{
+				Aggregation: calculatorpb.Aggregation_AGGREGATION_MIN, Duration: durationpb.New(time.Microsecond),
+			}, 1),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "options twice",
+			reqs: append(
+				windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_SUM, Size: 3}, 1),
+				windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_SUM, Size: 3})...,
+			),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not finite",
+			reqs:     windowRequests(&calculatorpb.WindowOptions{Aggregation: calculatorpb.Aggregation_AGGREGATION_SUM, Size: 3}, math.NaN()),
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.AggregateWindows(testContext(t))
+			if err != nil {
+				t.Fatalf("AggregateWindows: %v", err)
+			}
+			for _, req := range tt.reqs {
+				if err := stream.Send(req); err != nil {
+					break
+				}
+			}
+			stream.CloseSend()
+
+			var got []*calculatorpb.AggregateWindowsResponse
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					checkStatus(t, err, tt.wantCode, "")
+					return
+				}
+				got = append(got, res)
+			}
+			if tt.wantCode != codes.OK {
+				t.Fatalf("AggregateWindows succeeded, want %v", tt.wantCode)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("AggregateWindows: got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("window %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestAggregateWindowsTimer checks that the server's timer closes each
+// window a number fell into while the client is idle, in order.
+func TestAggregateWindowsTimer(t *testing.T) {
+	c := servertest.Calculator(t)
+	stream, err := c.AggregateWindows(testContext(t))
+	if err != nil {
+		t.Fatalf("AggregateWindows: %v", err)
+	}
+	send := func(reqs []*calculatorpb.AggregateWindowsRequest) {
+		for _, req := range reqs {
+			if err := stream.Send(req); err != nil {
+				t.Fatalf("AggregateWindows Send: %v", err)
+			}
+		}
+	}
+	recv := func(want float64) *calculatorpb.AggregateWindowsResponse {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("AggregateWindows Recv: %v", err)
+		}
+		if res.GetResult() != want || res.GetCount() != 1 {
+			t.Errorf("got window %v, want the one number %v", res, want)
+		}
+		if end := res.GetEnd().AsTime(); time.Now().Before(end) {
+			t.Errorf("window ending at %v was sent before it ended", end)
+		}
+		return res
+	}
+
+	// Windows of 20ms start every 10ms from the options, so a number sent
+	// at once falls into the first window only.
+	send(windowRequests(&calculatorpb.WindowOptions{
+		Aggregation:   calculatorpb.Aggregation_AGGREGATION_MAX,
+		Duration:      durationpb.New(20 * time.Millisecond),
+		SlideDuration: durationpb.New(10 * time.Millisecond),
+	}, 7))
+	first := recv(7)
+
+	// After the first window, a number falls into two, both closed by the
+	// timer.
+	send(windowRequests(nil, 8))
+	second, third := recv(8), recv(8)
+	if d := third.GetStart().AsTime().Sub(second.GetStart().AsTime()); d != 10*time.Millisecond {
+		t.Errorf("windows start %v apart, want 10ms", d)
+	}
+	if !second.GetStart().AsTime().After(first.GetStart().AsTime()) {
+		t.Errorf("window %v sent after %v", second, first)
+	}
+
+	// Nothing is left for the end of the stream.
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("AggregateWindows CloseSend: %v", err)
+	}
+	if res, err := stream.Recv(); err != io.EOF {
+		t.Errorf("AggregateWindows after CloseSend: got %v, %v, want io.EOF", res, err)
+	}
+}
+
+func TestAggregateWindowsByTime(t *testing.T) {
+	c := servertest.Calculator(t)
+	stream, err := c.AggregateWindows(testContext(t))
+	if err != nil {
+		t.Fatalf("AggregateWindows: %v", err)
+	}
+	for _, req := range windowRequests(&calculatorpb.WindowOptions{
+		Aggregation: calculatorpb.Aggregation_AGGREGATION_SUM,
+		Duration:    durationpb.New(20 * time.Millisecond),
+	}, 1, 2) {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("AggregateWindows Send: %v", err)
+		}
+	}
+
+	// The window closes while the client is idle.
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("AggregateWindows Recv: %v", err)
+	}
+	if res.GetStart() == nil || res.GetEnd() == nil {
+		t.Fatalf("AggregateWindows: time window %v without start and end", res)
+	}
+	if d := res.GetEnd().AsTime().Sub(res.GetStart().AsTime()); d != 20*time.Millisecond {
+		t.Errorf("AggregateWindows: window lasts %v, want 20ms", d)
+	}
+	// Both numbers usually land in the same window, but may straddle two.
+	if res.GetResult() != 3 && res.GetResult() != 1 {
+		t.Errorf("AggregateWindows: got sum %v, want 3 or 1", res.GetResult())
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("AggregateWindows CloseSend: %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("AggregateWindows Recv: %v", err)
+		}
+	}
+}
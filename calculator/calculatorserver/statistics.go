@@ -0,0 +1,159 @@
+package calculatorserver
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/stats"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc/codes"
+)
+
+// maxStatisticsCount bounds how many numbers one statistics stream may send,
+// since all of them are kept for the median and percentiles.
+const maxStatisticsCount = 1 << 20
+
+// maxRunningWork bounds the numbers moved while sorting for the summaries of
+// one running statistics stream. Every summary merges the numbers added since
+// the last one into those already sorted, so n numbers summarized every e
+// cost up to n*n/(2*e) moves.
+const maxRunningWork = 1 << 30
+
+// statisticsStream is the receiving side shared by ComputeStatistics and
+// ComputeRunningStatistics.
+type statisticsStream interface {
+	Recv() (*calculatorpb.ComputeStatisticsRequest, error)
+}
+
+// statisticsReader reads numbers from a statistics stream into an
+// accumulator.
+type statisticsReader struct {
+	stream  statisticsStream
+	options *calculatorpb.StatisticsOptions
+	acc     stats.Accumulator
+	started bool
+	// running is set for ComputeRunningStatistics, which summarizes the
+	// numbers as they arrive.
+	running bool
+}
+
+// next adds the next number of the stream. It returns io.EOF at the end of
+// the stream.
+func (r *statisticsReader) next() error {
+	for {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return err
+		}
+		first := !r.started
+		r.started = true
+
+		switch v := req.GetRequest().(type) {
+		case *calculatorpb.ComputeStatisticsRequest_Options:
+			if !first {
+				return domain.Error(codes.InvalidArgument, ReasonStreamOrder, "options must be the first message of the stream")
+			}
+			if err := stats.ValidatePercentiles(v.Options.GetPercentiles()); err != nil {
+				return domain.InvalidField("", "options.percentiles", err.Error())
+			}
+			if v.Options.GetEmitEvery() < 0 {
+				return domain.InvalidField("", "options.emit_every", fmt.Sprintf("emit_every must not be negative, got %d", v.Options.GetEmitEvery()))
+			}
+			r.options = v.Options
+		case *calculatorpb.ComputeStatisticsRequest_Number:
+			if math.IsNaN(v.Number) || math.IsInf(v.Number, 0) {
+				return domain.InvalidField("", "number", fmt.Sprintf("number must be finite, got %v", v.Number))
+			}
+			if limit := r.limit(); r.acc.Count() >= limit {
+				return domain.Errorf(codes.ResourceExhausted, ReasonStreamTooLong, "at most %d numbers are accepted", limit)
+			}
+			r.acc.Add(v.Number)
+			return nil
+		default:
+			return domain.Error(codes.InvalidArgument, rpcerr.ReasonInvalidField, "request must set options or number", rpcerr.FieldViolation("request", "request must set options or number"))
+		}
+	}
+}
+
+func (r *statisticsReader) emitEvery() int {
+	if n := r.options.GetEmitEvery(); n > 0 {
+		return int(n)
+	}
+	return 1
+}
+
+// limit returns how many numbers the stream may send.
+func (r *statisticsReader) limit() int {
+	if !r.running {
+		return maxStatisticsCount
+	}
+	limit := int(math.Sqrt(2 * maxRunningWork * float64(r.emitEvery())))
+	if limit > maxStatisticsCount {
+		return maxStatisticsCount
+	}
+	return limit
+}
+
+func (r *statisticsReader) summary() *calculatorpb.ComputeStatisticsResponse {
+	s := r.acc.Summary(r.options.GetPercentiles())
+	res := &calculatorpb.ComputeStatisticsResponse{
+		Count:    int64(s.Count),
+		Min:      s.Min,
+		Max:      s.Max,
+		Mean:     s.Mean,
+		Variance: s.Variance,
+		Stddev:   s.StdDev,
+		Median:   s.Median,
+	}
+	for _, p := range s.Percentiles {
+		res.Percentiles = append(res.Percentiles, &calculatorpb.Percentile{
+			Percentile: p.Percentile,
+			Value:      p.Value,
+		})
+	}
+	return res
+}
+
+func (*Server) ComputeStatistics(stream calculatorpb.CalculatorService_ComputeStatisticsServer) error {
+	requestid.Logf(stream.Context(), "ComputeStatistics function was invoked")
+	r := &statisticsReader{stream: stream}
+	for {
+		err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if r.acc.Count() == 0 {
+		return domain.Error(codes.InvalidArgument, ReasonEmptyStream, "no numbers received")
+	}
+	return stream.SendAndClose(r.summary())
+}
+
+func (*Server) ComputeRunningStatistics(stream calculatorpb.CalculatorService_ComputeRunningStatisticsServer) error {
+	requestid.Logf(stream.Context(), "ComputeRunningStatistics function was invoked")
+	r := &statisticsReader{stream: stream, running: true}
+	for {
+		err := r.next()
+		if err == io.EOF {
+			// Report the numbers after the last full batch too.
+			if n := r.acc.Count(); n > 0 && n%r.emitEvery() != 0 {
+				return stream.Send(r.summary())
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if r.acc.Count()%r.emitEvery() == 0 {
+			if err := stream.Send(r.summary()); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package calculatorserver
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/window"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// minWindowDuration keeps time windows from firing faster than responses
+// can usefully be sent.
+const minWindowDuration = time.Millisecond
+
+var aggregations = map[calculatorpb.Aggregation]window.Aggregation{
+	calculatorpb.Aggregation_AGGREGATION_MAX:  window.Max,
+	calculatorpb.Aggregation_AGGREGATION_MIN:  window.Min,
+	calculatorpb.Aggregation_AGGREGATION_SUM:  window.Sum,
+	calculatorpb.Aggregation_AGGREGATION_MEAN: window.Mean,
+}
+
+// windows is implemented by window.Count and window.Time.
+type windows interface {
+	Flush() []window.Result
+}
+
+func newWindows(opts *calculatorpb.WindowOptions, now time.Time) (windows, error) {
+	agg, ok := aggregations[opts.GetAggregation()]
+	if !ok {
+		return nil, domain.InvalidField("", "options.aggregation", fmt.Sprintf("unsupported aggregation %v", opts.GetAggregation()))
+	}
+
+	if opts.GetDuration() == nil {
+		if opts.GetSlideDuration() != nil {
+			return nil, domain.InvalidField("", "options.slide_duration", "slide_duration requires duration")
+		}
+		w, err := window.NewCount(agg, int(opts.GetSize()), int(opts.GetSlide()))
+		if err != nil {
+			return nil, domain.InvalidField("", "options", err.Error())
+		}
+		return w, nil
+	}
+
+	if opts.GetSize() != 0 || opts.GetSlide() != 0 {
+		return nil, domain.InvalidField("", "options", "set either size and slide or duration and slide_duration")
+	}
+	if err := opts.GetDuration().CheckValid(); err != nil {
+		return nil, domain.InvalidField("", "options.duration", fmt.Sprintf("invalid duration: %v", err))
+	}
+	size := opts.GetDuration().AsDuration()
+	slide := time.Duration(0)
+	if opts.GetSlideDuration() != nil {
+		if err := opts.GetSlideDuration().CheckValid(); err != nil {
+			return nil, domain.InvalidField("", "options.slide_duration", fmt.Sprintf("invalid slide_duration: %v", err))
+		}
+		slide = opts.GetSlideDuration().AsDuration()
+	}
+	if size < minWindowDuration || (slide != 0 && slide < minWindowDuration) {
+		return nil, domain.InvalidField("", "options.duration", fmt.Sprintf("window durations must be at least %v", minWindowDuration))
+	}
+	w, err := window.NewTime(agg, size, slide, now)
+	if err != nil {
+		return nil, domain.InvalidField("", "options", err.Error())
+	}
+	return w, nil
+}
+
+func windowResponse(r window.Result) *calculatorpb.AggregateWindowsResponse {
+	res := &calculatorpb.AggregateWindowsResponse{
+		Result: r.Value,
+		Count:  int64(r.Count),
+	}
+	if r.Start.IsZero() {
+		res.FirstIndex = r.First
+		res.LastIndex = r.Last
+	} else {
+		res.Start = timestamppb.New(r.Start)
+		res.End = timestamppb.New(r.End)
+	}
+	return res
+}
+
+func (*Server) AggregateWindows(stream calculatorpb.CalculatorService_AggregateWindowsServer) error {
+	ctx := stream.Context()
+	requestid.Logf(ctx, "AggregateWindows function was invoked")
+
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return domain.Error(codes.InvalidArgument, ReasonStreamOrder, "options must be the first message of the stream")
+	}
+	if err != nil {
+		return err
+	}
+	if req.GetOptions() == nil {
+		return domain.Error(codes.InvalidArgument, ReasonStreamOrder, "options must be the first message of the stream")
+	}
+	ws, err := newWindows(req.GetOptions(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	send := func(results []window.Result) error {
+		for _, r := range results {
+			if err := stream.Send(windowResponse(r)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Time windows close while the client may be idle, so receive in the
+	// background and wake up for whichever comes first.
+	type received struct {
+		req *calculatorpb.AggregateWindowsRequest
+		err error
+	}
+	requests := make(chan received)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			select {
+			case requests <- received{req, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		var wake <-chan time.Time
+		if tw, ok := ws.(*window.Time); ok {
+			if deadline, ok := tw.Deadline(); ok {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(time.Until(deadline))
+				wake = timer.C
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case now := <-wake:
+			if err := send(ws.(*window.Time).Advance(now)); err != nil {
+				return err
+			}
+
+		case r := <-requests:
+			if r.err == io.EOF {
+				return send(ws.Flush())
+			}
+			if r.err != nil {
+				return r.err
+			}
+			number, ok := r.req.GetRequest().(*calculatorpb.AggregateWindowsRequest_Number)
+			if !ok {
+				return domain.Error(codes.InvalidArgument, ReasonStreamOrder, "options may only be sent as the first message of the stream")
+			}
+			x := number.Number
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				return domain.InvalidField("", "number", fmt.Sprintf("number must be finite, got %v", x))
+			}
+			var results []window.Result
+			switch w := ws.(type) {
+			case *window.Count:
+				results = w.Add(x)
+			case *window.Time:
+				results = w.Add(time.Now(), x)
+			}
+			if err := send(results); err != nil {
+				return err
+			}
+		}
+	}
+}
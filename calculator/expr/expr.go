@@ -0,0 +1,503 @@
+// Package expr parses and evaluates the arithmetic expressions of the
+// calculator's Evaluate RPC.
+//
+// Expressions use the operators + - * / % and ^ (power, right associative),
+// parentheses, decimal numbers such as 2, 0.5 or 1e-3, the constants pi and
+// e, named variables and the functions listed in Functions. Unary minus binds
+// looser than ^, so -2^2 is -4.
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxLength is the longest expression Parse accepts, in bytes.
+	MaxLength = 4096
+	// maxDepth bounds the nesting of parentheses and unary operators.
+	maxDepth = 100
+)
+
+// Error is a parse or evaluation error. Pos is the 1-based character
+// position in the expression of the token that caused it.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+func errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Expr is a parsed expression.
+type Expr struct {
+	root node
+}
+
+// Parse parses s.
+func Parse(s string) (*Expr, error) {
+	if len(s) > MaxLength {
+		return nil, errorf(MaxLength+1, "expression is longer than %d bytes", MaxLength)
+	}
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, errorf(tok.pos, "unexpected %s", tok)
+	}
+	return &Expr{root: root}, nil
+}
+
+// Eval evaluates the expression with vars bound to their values. Variables
+// shadow the constants pi and e.
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errorf(e.root.position(), "result is not a finite number")
+	}
+	return v, nil
+}
+
+// Variables returns the names of the variables the expression uses, sorted.
+func (e *Expr) Variables() []string {
+	seen := map[string]bool{}
+	e.root.walk(func(n node) {
+		if v, ok := n.(*variable); ok {
+			seen[v.name] = true
+		}
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Evaluate parses and evaluates s.
+func Evaluate(s string, vars map[string]float64) (float64, error) {
+	e, err := Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return e.Eval(vars)
+}
+
+// ValidName reports whether name can be used as a variable.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !isIdentRune(r) || (i == 0 && isDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Functions lists the function names expressions may call.
+func Functions() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type function struct {
+	minArgs, maxArgs int
+	call             func(pos int, args []float64) (float64, error)
+}
+
+func unary(f func(float64) float64) function {
+	return function{1, 1, func(_ int, args []float64) (float64, error) { return f(args[0]), nil }}
+}
+
+var functions = map[string]function{
+	"abs":   unary(math.Abs),
+	"ceil":  unary(math.Ceil),
+	"cos":   unary(math.Cos),
+	"exp":   unary(math.Exp),
+	"floor": unary(math.Floor),
+	"round": unary(math.Round),
+	"sin":   unary(math.Sin),
+	"tan":   unary(math.Tan),
+	"sqrt": {1, 1, func(pos int, args []float64) (float64, error) {
+		if args[0] < 0 {
+			return 0, errorf(pos, "sqrt of negative number %v", args[0])
+		}
+		return math.Sqrt(args[0]), nil
+	}},
+	// log(x) is the natural logarithm, log(x, b) the logarithm in base b.
+	"log": {1, 2, func(pos int, args []float64) (float64, error) {
+		if args[0] <= 0 {
+			return 0, errorf(pos, "log of non-positive number %v", args[0])
+		}
+		if len(args) == 1 {
+			return math.Log(args[0]), nil
+		}
+		if args[1] <= 0 || args[1] == 1 {
+			return 0, errorf(pos, "invalid logarithm base %v", args[1])
+		}
+		return math.Log(args[0]) / math.Log(args[1]), nil
+	}},
+	"pow": {2, 2, func(_ int, args []float64) (float64, error) {
+		return math.Pow(args[0], args[1]), nil
+	}},
+	"min": {1, -1, func(_ int, args []float64) (float64, error) {
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Min(m, a)
+		}
+		return m, nil
+	}},
+	"max": {1, -1, func(_ int, args []float64) (float64, error) {
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Max(m, a)
+		}
+		return m, nil
+	}},
+}
+
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// node is an element of the parsed expression tree.
+type node interface {
+	eval(vars map[string]float64) (float64, error)
+	position() int
+	walk(fn func(node))
+}
+
+type number struct {
+	pos   int
+	value float64
+}
+
+func (n *number) eval(map[string]float64) (float64, error) { return n.value, nil }
+func (n *number) position() int                            { return n.pos }
+func (n *number) walk(fn func(node))                       { fn(n) }
+
+type variable struct {
+	pos  int
+	name string
+}
+
+func (v *variable) eval(vars map[string]float64) (float64, error) {
+	if value, ok := vars[v.name]; ok {
+		return value, nil
+	}
+	if value, ok := constants[v.name]; ok {
+		return value, nil
+	}
+	return 0, errorf(v.pos, "undefined variable %q", v.name)
+}
+
+func (v *variable) position() int      { return v.pos }
+func (v *variable) walk(fn func(node)) { fn(v) }
+
+type negate struct {
+	pos     int
+	operand node
+}
+
+func (n *negate) eval(vars map[string]float64) (float64, error) {
+	v, err := n.operand.eval(vars)
+	return -v, err
+}
+
+func (n *negate) position() int { return n.pos }
+
+func (n *negate) walk(fn func(node)) {
+	fn(n)
+	n.operand.walk(fn)
+}
+
+type binary struct {
+	pos         int
+	op          rune
+	left, right node
+}
+
+func (b *binary) eval(vars map[string]float64) (float64, error) {
+	l, err := b.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	switch b.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, errorf(b.pos, "division by zero")
+		}
+		return l / r, nil
+	case '%':
+		if r == 0 {
+			return 0, errorf(b.pos, "modulo by zero")
+		}
+		return math.Mod(l, r), nil
+	case '^':
+		return math.Pow(l, r), nil
+	}
+	return 0, errorf(b.pos, "unknown operator %q", b.op)
+}
+
+func (b *binary) position() int { return b.pos }
+
+func (b *binary) walk(fn func(node)) {
+	fn(b)
+	b.left.walk(fn)
+	b.right.walk(fn)
+}
+
+type call struct {
+	pos  int
+	name string
+	fn   function
+	args []node
+}
+
+func (c *call) eval(vars map[string]float64) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	return c.fn.call(c.pos, args)
+}
+
+func (c *call) position() int { return c.pos }
+
+func (c *call) walk(fn func(node)) {
+	fn(c)
+	for _, arg := range c.args {
+		arg.walk(fn)
+	}
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token { return p.tokens[p.next] }
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.advance()
+	if tok.kind != kind {
+		return tok, errorf(tok.pos, "expected %s, found %s", what, tok)
+	}
+	return tok, nil
+}
+
+// parseExpr parses a sum: term (('+' | '-') term)*.
+func (p *parser) parseExpr(depth int) (node, error) {
+	left, err := p.parseTerm(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOperator || (tok.op != '+' && tok.op != '-') {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseTerm(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{pos: tok.pos, op: tok.op, left: left, right: right}
+	}
+}
+
+// parseTerm parses a product: unary (('*' | '/' | '%') unary)*.
+func (p *parser) parseTerm(depth int) (node, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOperator || (tok.op != '*' && tok.op != '/' && tok.op != '%') {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{pos: tok.pos, op: tok.op, left: left, right: right}
+	}
+}
+
+// parseUnary parses ('+' | '-')* power.
+func (p *parser) parseUnary(depth int) (node, error) {
+	tok := p.peek()
+	if tok.kind == tokOperator && (tok.op == '+' || tok.op == '-') {
+		if depth >= maxDepth {
+			return nil, errorf(tok.pos, "expression is nested too deeply")
+		}
+		p.advance()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if tok.op == '+' {
+			return operand, nil
+		}
+		return &negate{pos: tok.pos, operand: operand}, nil
+	}
+	return p.parsePower(depth)
+}
+
+// parsePower parses primary ('^' unary)?, making ^ right associative.
+func (p *parser) parsePower(depth int) (node, error) {
+	base, err := p.parsePrimary(depth)
+	if err != nil {
+		return nil, err
+	}
+	tok := p.peek()
+	if tok.kind != tokOperator || tok.op != '^' {
+		return base, nil
+	}
+	if depth >= maxDepth {
+		return nil, errorf(tok.pos, "expression is nested too deeply")
+	}
+	p.advance()
+	exponent, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	return &binary{pos: tok.pos, op: '^', left: base, right: exponent}, nil
+}
+
+// parsePrimary parses a number, a variable, a function call or a
+// parenthesized expression.
+func (p *parser) parsePrimary(depth int) (node, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokNumber:
+		return &number{pos: tok.pos, value: tok.value}, nil
+
+	case tokIdent:
+		if next := p.peek(); next.kind != tokLParen {
+			return &variable{pos: tok.pos, name: tok.text}, nil
+		}
+		fn, ok := functions[tok.text]
+		if !ok {
+			return nil, errorf(tok.pos, "unknown function %q", tok.text)
+		}
+		if depth >= maxDepth {
+			return nil, errorf(tok.pos, "expression is nested too deeply")
+		}
+		p.advance()
+		args, err := p.parseArgs(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+			return nil, errorf(tok.pos, "%s takes %s, got %d", tok.text, arity(fn), len(args))
+		}
+		return &call{pos: tok.pos, name: tok.text, fn: fn, args: args}, nil
+
+	case tokLParen:
+		if depth >= maxDepth {
+			return nil, errorf(tok.pos, "expression is nested too deeply")
+		}
+		inner, err := p.parseExpr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	return nil, errorf(tok.pos, "expected a number, variable, function or '(', found %s", tok)
+}
+
+// parseArgs parses a comma separated argument list up to and including the
+// closing parenthesis.
+func (p *parser) parseArgs(depth int) ([]node, error) {
+	var args []node
+	if p.peek().kind == tokRParen {
+		p.advance()
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpr(depth)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		tok := p.advance()
+		switch tok.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return args, nil
+		}
+		return nil, errorf(tok.pos, "expected ',' or ')', found %s", tok)
+	}
+}
+
+func arity(fn function) string {
+	switch {
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", fn.minArgs)
+	case fn.minArgs == fn.maxArgs && fn.minArgs == 1:
+		return "1 argument"
+	case fn.minArgs == fn.maxArgs:
+		return fmt.Sprintf("%d arguments", fn.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", fn.minArgs, fn.maxArgs)
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "\\'") + "'"
+}
@@ -0,0 +1,167 @@
+package expr
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	vars := map[string]float64{"x": 3, "y_2": -2, "pi": 3}
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		// Numbers.
+		{"42", 42},
+		{"0.5", 0.5},
+		{".5", 0.5},
+		{"1e3", 1000},
+		{"1.5E-1", 0.15},
+
+		// Precedence and associativity.
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"100 / 10 / 5", 2},
+		{"7 % 4 * 2", 6},
+		{"2 * 3 ^ 2", 18},
+		{"2 ^ 3 ^ 2", 512},
+		{"(2 ^ 3) ^ 2", 64},
+
+		// Unary minus binds looser than ^ and tighter than * and +.
+		{"-2 ^ 2", -4},
+		{"(-2) ^ 2", 4},
+		{"2 ^ -1", 0.5},
+		{"-3 * -3", 9},
+		{"--3", 3},
+		{"+-+3", -3},
+		{"1 - -1", 2},
+		{"-x", -3},
+
+		// Parentheses.
+		{"((((7))))", 7},
+		{"(1 + (2 * (3 + (4))))", 15},
+
+		// Variables, constants and functions.
+		{"x * y_2", -6},
+		{"pi", 3}, // variables shadow constants
+		{"e", math.E},
+		{"sqrt(16) + abs(-2)", 6},
+		{"log(8, 2)", 3},
+		{"max(1, x, 2)", 3},
+		{"min(x)", 3},
+		{"pow(2, 10)", 1024},
+		{"floor(-0.5) + ceil(0.5) + round(2.5)", 3},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expr, vars)
+		if err != nil {
+			t.Errorf("Evaluate(%q): %v", tt.expr, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		pos     int
+		message string
+	}{
+		{"", 1, "expected a number"},
+		{"1 +", 4, "expected a number"},
+		{"2 * (1", 7, "expected ')', found end of expression"},
+		{"(1))", 4, "unexpected ')'"},
+		{"1 2", 3, "unexpected number 2"},
+		{"2e", 2, "unexpected name 'e'"},
+		{"1.2.3", 4, "unexpected number .3"},
+		{". + 1", 1, "invalid number '.'"},
+		{"2 $ 3", 3, "unexpected character '$'"},
+		// Positions count characters, not bytes.
+		{"é + $", 5, "unexpected character '$'"},
+		{"\xff", 1, "invalid UTF-8"},
+		{"foo(1)", 1, `unknown function "foo"`},
+		{"sqrt(1, 2)", 1, "sqrt takes 1 argument, got 2"},
+		{"log()", 1, "log takes 1 to 2 arguments, got 0"},
+		{"max(1 2)", 7, "expected ',' or ')'"},
+
+		// Evaluation errors point at the operator or call.
+		{"1 + 4 / (2 - 2)", 7, "division by zero"},
+		{"5 % 0", 3, "modulo by zero"},
+		{"1 + z", 5, `undefined variable "z"`},
+		{"2 * sqrt(-1)", 5, "sqrt of negative number -1"},
+		{"log(2, 1)", 1, "invalid logarithm base 1"},
+		{"10 ^ 400", 4, "result is not a finite number"},
+	}
+	for _, tt := range tests {
+		_, err := Evaluate(tt.expr, nil)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("Evaluate(%q): got %v, want an *Error", tt.expr, err)
+			continue
+		}
+		if e.Pos != tt.pos || !strings.Contains(e.Msg, tt.message) {
+			t.Errorf("Evaluate(%q): got %q at %d, want %q at %d", tt.expr, e.Msg, e.Pos, tt.message, tt.pos)
+		}
+	}
+}
+
+func TestNesting(t *testing.T) {
+	tests := []struct {
+		name    string
+		nest    func(n int) string
+		tooDeep int // position of the token over the limit, at maxDepth+1
+	}{
+		{"parentheses", func(n int) string { return strings.Repeat("(", n) + "1" + strings.Repeat(")", n) }, maxDepth + 1},
+		{"unary minus", func(n int) string { return strings.Repeat("-", n) + "1" }, maxDepth + 1},
+		{"functions", func(n int) string { return strings.Repeat("abs(", n) + "1" + strings.Repeat(")", n) }, 4*maxDepth + 1},
+		{"powers", func(n int) string { return "1" + strings.Repeat("^1", n) }, 2*maxDepth + 2},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.nest(maxDepth)); err != nil {
+			t.Errorf("%s nested %d deep: %v", tt.name, maxDepth, err)
+		}
+		_, err := Parse(tt.nest(maxDepth + 1))
+		if e, ok := err.(*Error); !ok || e.Pos != tt.tooDeep || !strings.Contains(e.Msg, "nested too deeply") {
+			t.Errorf("%s nested %d deep: got %v, want too deep at position %d", tt.name, maxDepth+1, err, tt.tooDeep)
+		}
+	}
+
+	// Chains of binary operators parse iteratively, so only MaxLength
+	// bounds them.
+	if _, err := Parse(strings.Repeat("1+", MaxLength/2-1) + "1"); err != nil {
+		t.Errorf("long sum: %v", err)
+	}
+	if _, err := Parse(strings.Repeat("1", MaxLength+1)); err == nil {
+		t.Errorf("expression over MaxLength: got no error")
+	}
+}
+
+func TestVariables(t *testing.T) {
+	e, err := Parse("b * max(a, c, b) - pi + sqrt(a)")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, want := e.Variables(), []string{"a", "b", "c", "pi"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+	if _, err := e.Eval(map[string]float64{"a": 4, "b": 1}); err == nil {
+		t.Errorf("Eval without c: got no error")
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"x": true, "rate_2": true, "_": true, "été": true,
+		"": false, "2x": false, "a-b": false, "a b": false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
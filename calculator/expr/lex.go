@@ -0,0 +1,138 @@
+package expr
+
+import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind  tokenKind
+	pos   int // 1-based character position
+	text  string
+	op    rune
+	value float64
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokNumber:
+		return "number " + t.text
+	case tokIdent:
+		return "name " + quote(t.text)
+	}
+	return quote(t.text)
+}
+
+// lex splits s into tokens, always ending with a tokEOF.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	pos := 0 // characters consumed so far
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		start := pos + 1
+		switch {
+		case r == utf8.RuneError && size == 1:
+			return nil, errorf(start, "invalid UTF-8")
+
+		case unicode.IsSpace(r):
+			i += size
+			pos++
+
+		case isDigit(r) || r == '.':
+			n := scanNumber(s[i:])
+			text := s[i : i+n]
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, errorf(start, "invalid number %s", quote(text))
+			}
+			tokens = append(tokens, token{kind: tokNumber, pos: start, text: text, value: value})
+			i += n
+			pos += n
+
+		case isIdentRune(r):
+			j, chars := i, 0
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !isIdentRune(r) {
+					break
+				}
+				j += size
+				chars++
+			}
+			tokens = append(tokens, token{kind: tokIdent, pos: start, text: s[i:j]})
+			i = j
+			pos += chars
+
+		default:
+			tok := token{pos: start, text: string(r), op: r}
+			switch r {
+			case '+', '-', '*', '/', '%', '^':
+				tok.kind = tokOperator
+			case '(':
+				tok.kind = tokLParen
+			case ')':
+				tok.kind = tokRParen
+			case ',':
+				tok.kind = tokComma
+			default:
+				return nil, errorf(start, "unexpected character %s", quote(string(r)))
+			}
+			tokens = append(tokens, tok)
+			i += size
+			pos++
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: pos + 1}), nil
+}
+
+// scanNumber returns the length of the number at the start of s: digits
+// with an optional fraction and exponent. An 'e' only belongs to the number
+// when digits follow it.
+func scanNumber(s string) int {
+	i := 0
+	for i < len(s) && isDigit(rune(s[i])) {
+		i++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && isDigit(rune(s[i])) {
+			i++
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(rune(s[j])) {
+			for j < len(s) && isDigit(rune(s[j])) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
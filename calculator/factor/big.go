@@ -0,0 +1,222 @@
+package factor
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+)
+
+// BigEmitFunc receives each distinct prime factor of a big integer with its
+// multiplicity. The factor must not be retained after the call returns.
+type BigEmitFunc func(prime *big.Int, multiplicity int) error
+
+// millerRabinRounds is the number of random bases ProbablyPrime tries on top
+// of its Baillie-PSW test.
+const millerRabinRounds = 20
+
+// FactorBig is Factor for integers of any size. Once the unfactored part
+// fits in 64 bits it continues with Factor. Factors of more than 64 bits are
+// only probably prime, with an error chance far below 4^-20.
+func FactorBig(ctx context.Context, n *big.Int, emit BigEmitFunc) error {
+	if n.Sign() <= 0 {
+		return nil
+	}
+	rest := new(big.Int).Set(n)
+
+	emitSmall := func(p uint64, m int) error {
+		return emit(new(big.Int).SetUint64(p), m)
+	}
+	if rest.IsUint64() {
+		return Factor(ctx, rest.Uint64(), emitSmall)
+	}
+
+	// Trial division first so the rho walks only see large factors.
+	mod := new(big.Int)
+	for _, p := range smallPrimes {
+		bp := new(big.Int).SetUint64(p)
+		m := 0
+		for {
+			q, r := new(big.Int).QuoRem(rest, bp, mod)
+			if r.Sign() != 0 {
+				break
+			}
+			rest = q
+			m++
+		}
+		if m > 0 {
+			if err := emit(bp, m); err != nil {
+				return err
+			}
+		}
+	}
+
+	one := big.NewInt(1)
+	for rest.Cmp(one) > 0 {
+		if rest.IsUint64() {
+			return factorRest(ctx, rest.Uint64(), emitSmall)
+		}
+		p, err := bigPrimeFactor(ctx, rest)
+		if err != nil {
+			return err
+		}
+		m := 0
+		for {
+			q, r := new(big.Int).QuoRem(rest, p, mod)
+			if r.Sign() != 0 {
+				break
+			}
+			rest = q
+			m++
+		}
+		if err := emit(p, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bigPrimeFactor returns some prime factor of n > 1.
+func bigPrimeFactor(ctx context.Context, n *big.Int) (*big.Int, error) {
+	n = new(big.Int).Set(n)
+	for {
+		if n.IsUint64() {
+			p, err := primeFactor(ctx, n.Uint64())
+			if err != nil {
+				return nil, err
+			}
+			return new(big.Int).SetUint64(p), nil
+		}
+		if n.ProbablyPrime(millerRabinRounds) {
+			return n, nil
+		}
+		// Rho needs about sqrt(p) steps for a factor p, which is hopeless
+		// for p^k with a large p, while the root is cheap to find directly.
+		root, err := perfectPowerRoot(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		if root != nil {
+			n = root
+			continue
+		}
+		d, err := bigRho(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		if other := new(big.Int).Quo(n, d); other.Cmp(d) < 0 {
+			d = other
+		}
+		n = d
+	}
+}
+
+// bigRho returns a non-trivial factor of the composite n.
+func bigRho(ctx context.Context, n *big.Int) (*big.Int, error) {
+	if n.Bit(0) == 0 {
+		return big.NewInt(2), nil
+	}
+	rng := rand.New(rand.NewSource(n.Int64()))
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1))
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := new(big.Int).Rand(rng, nMinus1)
+		c.Add(c, big.NewInt(1))
+		y := new(big.Int).Rand(rng, n)
+		d, err := bigBrent(ctx, n, c, y)
+		if err != nil {
+			return nil, err
+		}
+		if d.Cmp(n) != 0 {
+			return d, nil
+		}
+	}
+}
+
+// bigBrent is brent for big integers. It returns n when the walk failed and
+// must be retried with another c.
+func bigBrent(ctx context.Context, n, c, y *big.Int) (*big.Int, error) {
+	const batch = 128
+	f := func(x *big.Int) {
+		x.Mul(x, x)
+		x.Add(x, c)
+		x.Mod(x, n)
+	}
+	diff := new(big.Int)
+	absDiff := func(a, b *big.Int) *big.Int {
+		return diff.Abs(diff.Sub(a, b))
+	}
+
+	one := big.NewInt(1)
+	x, ys := new(big.Int), new(big.Int)
+	g, q := big.NewInt(1), big.NewInt(1)
+	for r := 1; g.Cmp(one) == 0; r *= 2 {
+		x.Set(y)
+		for i := 0; i < r; i++ {
+			if i%batch == 0 && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			f(y)
+		}
+		for k := 0; k < r && g.Cmp(one) == 0; k += batch {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			ys.Set(y)
+			for i := 0; i < batch && i < r-k; i++ {
+				f(y)
+				q.Mul(q, absDiff(x, y))
+				q.Mod(q, n)
+			}
+			g.GCD(nil, nil, q, n)
+		}
+	}
+	if g.Cmp(n) == 0 {
+		// The batched product overshot; step back one value at a time.
+		for {
+			f(ys)
+			if g.GCD(nil, nil, absDiff(x, ys), n); g.Cmp(one) > 0 {
+				break
+			}
+		}
+	}
+	return g, nil
+}
+
+// perfectPowerRoot returns r when n = r^k for some prime k, or nil. Only
+// prime exponents need checking since r^(ab) = (r^a)^b.
+func perfectPowerRoot(ctx context.Context, n *big.Int) (*big.Int, error) {
+	for _, k := range sieve(n.BitLen() + 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r := nthRoot(n, int(k))
+		if r.Cmp(big.NewInt(1)) > 0 && new(big.Int).Exp(r, big.NewInt(int64(k)), nil).Cmp(n) == 0 {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// nthRoot returns floor(n^(1/k)) for n > 0 using Newton's method.
+func nthRoot(n *big.Int, k int) *big.Int {
+	if k == 2 {
+		return new(big.Int).Sqrt(n)
+	}
+	bk := big.NewInt(int64(k))
+	bk1 := big.NewInt(int64(k - 1))
+	// Start above the root: 2^ceil(bitlen/k).
+	x := new(big.Int).Lsh(big.NewInt(1), uint(n.BitLen()/k+1))
+	for {
+		// y = ((k-1)x + n/x^(k-1)) / k
+		y := new(big.Int).Exp(x, bk1, nil)
+		y.Quo(n, y)
+		y.Add(y, new(big.Int).Mul(bk1, x))
+		y.Quo(y, bk)
+		if y.Cmp(x) >= 0 {
+			return x
+		}
+		x = y
+	}
+}
@@ -0,0 +1,257 @@
+// Package factor decomposes integers into prime factors. Small factors are
+// found by trial division, the rest with Pollard's rho (Brent's variant) and
+// a Miller-Rabin primality test, which is deterministic for 64-bit inputs.
+package factor
+
+import (
+	"context"
+	"math/bits"
+	"math/rand"
+)
+
+// trialLimit is the bound below which factors are found by trial division.
+const trialLimit = 1 << 10
+
+var smallPrimes = sieve(trialLimit)
+
+func sieve(limit int) []uint64 {
+	composite := make([]bool, limit)
+	var primes []uint64
+	for i := 2; i < limit; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, uint64(i))
+		for j := i * i; j < limit; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// EmitFunc receives each distinct prime factor with its multiplicity.
+// Returning an error stops the factorization with that error.
+type EmitFunc func(prime uint64, multiplicity int) error
+
+// Factor calls emit once for every distinct prime factor of n. Factors below
+// 1024 come first in ascending order; larger ones follow in the order they
+// are found. Factor returns ctx.Err() if ctx is done before it finishes, and
+// emits nothing for n < 2.
+func Factor(ctx context.Context, n uint64, emit EmitFunc) error {
+	rest, err := trialDivide(n, emit)
+	if err != nil || rest == 1 {
+		return err
+	}
+	return factorRest(ctx, rest, emit)
+}
+
+// trialDivide emits the small prime factors of n and returns what is left.
+func trialDivide(n uint64, emit EmitFunc) (uint64, error) {
+	if n < 2 {
+		return 1, nil
+	}
+	for _, p := range smallPrimes {
+		if p*p > n {
+			break
+		}
+		if n%p != 0 {
+			continue
+		}
+		m := 0
+		for n%p == 0 {
+			n /= p
+			m++
+		}
+		if err := emit(p, m); err != nil {
+			return 0, err
+		}
+	}
+	// With no factor up to sqrt(n) left, n is 1 or prime.
+	if n > 1 && n < trialLimit*trialLimit {
+		if err := emit(n, 1); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	return n, nil
+}
+
+// factorRest emits the prime factors of rest, which has none below
+// trialLimit.
+func factorRest(ctx context.Context, rest uint64, emit EmitFunc) error {
+	for rest > 1 {
+		p, err := primeFactor(ctx, rest)
+		if err != nil {
+			return err
+		}
+		m := 0
+		for rest%p == 0 {
+			rest /= p
+			m++
+		}
+		if err := emit(p, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// primeFactor returns some prime factor of n > 1.
+func primeFactor(ctx context.Context, n uint64) (uint64, error) {
+	for !IsPrime(n) {
+		d, err := rho(ctx, n)
+		if err != nil {
+			return 0, err
+		}
+		// Keep splitting the smaller side until it is prime.
+		if other := n / d; other < d {
+			d = other
+		}
+		n = d
+	}
+	return n, nil
+}
+
+// rho returns a non-trivial factor of the odd composite n.
+func rho(ctx context.Context, n uint64) (uint64, error) {
+	if n%2 == 0 {
+		return 2, nil
+	}
+	rng := rand.New(rand.NewSource(int64(n)))
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		c := rng.Uint64()%(n-1) + 1
+		y := rng.Uint64() % n
+		if d := brent(ctx, n, c, y); d != n && d != 0 {
+			return d, nil
+		}
+	}
+}
+
+// brent runs Brent's cycle detection on x -> x^2 + c mod n. It returns n
+// when the walk failed and must be retried with another c, and 0 when ctx
+// is done.
+func brent(ctx context.Context, n, c, y uint64) uint64 {
+	const batch = 128
+	f := func(x uint64) uint64 { return addMod(mulMod(x, x, n), c, n) }
+
+	var x, ys uint64
+	g, q := uint64(1), uint64(1)
+	for r := 1; g == 1; r *= 2 {
+		if ctx.Err() != nil {
+			return 0
+		}
+		x = y
+		for i := 0; i < r; i++ {
+			y = f(y)
+		}
+		for k := 0; k < r && g == 1; k += batch {
+			ys = y
+			for i := 0; i < batch && i < r-k; i++ {
+				y = f(y)
+				q = mulMod(q, absDiff(x, y), n)
+			}
+			g = gcd(q, n)
+		}
+	}
+	if g == n {
+		// The batched product overshot; step back one value at a time.
+		for {
+			ys = f(ys)
+			if g = gcd(absDiff(x, ys), n); g > 1 {
+				break
+			}
+		}
+	}
+	return g
+}
+
+// IsPrime reports whether n is prime. The Miller-Rabin bases used are known
+// to give the right answer for every 64-bit n.
+func IsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range smallPrimes[:12] {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	if n < 37*37 {
+		return true
+	}
+
+	d, s := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		s++
+	}
+	for _, a := range []uint64{2, 325, 9375, 28178, 450775, 9780504, 1795265022} {
+		a %= n
+		if a == 0 {
+			continue
+		}
+		if !millerRabin(n, d, s, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// millerRabin reports whether n = d*2^s + 1 passes the test for base a.
+func millerRabin(n, d uint64, s int, a uint64) bool {
+	x := powMod(a, d, n)
+	if x == 1 || x == n-1 {
+		return true
+	}
+	for i := 1; i < s; i++ {
+		x = mulMod(x, x, n)
+		if x == n-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
+
+func addMod(a, b, m uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 || sum >= m {
+		sum -= m
+	}
+	return sum
+}
+
+func powMod(a, e, m uint64) uint64 {
+	result := uint64(1)
+	a %= m
+	for e > 0 {
+		if e&1 == 1 {
+			result = mulMod(result, a, m)
+		}
+		a = mulMod(a, a, m)
+		e >>= 1
+	}
+	return result
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
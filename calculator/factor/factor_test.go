@@ -0,0 +1,212 @@
+package factor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestIsPrime(t *testing.T) {
+	primes := []uint64{
+		2, 3, 5, 37, 1021, 1031, 65537, 998244353, 1000000007,
+		4294967291,           // 2^32 - 5
+		68719476731,          // 2^36 - 5
+		2305843009213693951,  // 2^61 - 1
+		18446744073709551557, // the largest 64-bit prime
+	}
+	composites := []uint64{
+		0, 1, 4, 1369, // 37^2
+		// Carmichael numbers fool the Fermat test for every coprime base.
+		561, 1105, 1729, 2465, 2821, 6601, 8911, 41041, 825265, 321197185,
+		// Strong pseudoprimes to the bases 2, 3, 5 and 7, and to every
+		// prime base up to 23.
+		3215031751, 3825123056546413051,
+		4294967291 * 4294967279,
+		math.MaxUint64,
+	}
+	for _, n := range primes {
+		if !IsPrime(n) {
+			t.Errorf("IsPrime(%d) = false, want true", n)
+		}
+	}
+	for _, n := range composites {
+		if IsPrime(n) {
+			t.Errorf("IsPrime(%d) = true, want false", n)
+		}
+	}
+
+	// Every small number against a sieve, and random large ones against
+	// math/big.
+	const limit = 1 << 17
+	isPrime := make([]bool, limit)
+	for _, p := range sieve(limit) {
+		isPrime[p] = true
+	}
+	for n := uint64(0); n < limit; n++ {
+		if IsPrime(n) != isPrime[n] {
+			t.Fatalf("IsPrime(%d) = %v, want %v", n, !isPrime[n], isPrime[n])
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		n := rng.Uint64() | 1
+		if want := new(big.Int).SetUint64(n).ProbablyPrime(20); IsPrime(n) != want {
+			t.Fatalf("IsPrime(%d) = %v, want %v", n, !want, want)
+		}
+	}
+}
+
+// factorize collects the factors Factor emits, checking that they are
+// distinct primes.
+func factorize(t *testing.T, n uint64) map[uint64]int {
+	t.Helper()
+	got := map[uint64]int{}
+	err := Factor(testContext(t), n, func(p uint64, m int) error {
+		if _, ok := got[p]; ok {
+			t.Errorf("Factor(%d) emitted %d twice", n, p)
+		}
+		if !IsPrime(p) || m < 1 {
+			t.Errorf("Factor(%d) emitted %d^%d", n, p, m)
+		}
+		got[p] = m
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Factor(%d): %v", n, err)
+	}
+	return got
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want map[uint64]int
+	}{
+		{0, map[uint64]int{}},
+		{1, map[uint64]int{}},
+		{2, map[uint64]int{2: 1}},
+		{1 << 63, map[uint64]int{2: 63}},
+		{561, map[uint64]int{3: 1, 11: 1, 17: 1}},
+		{3825123056546413051, map[uint64]int{149491: 1, 747451: 1, 34233211: 1}},
+		// Prime powers, with and without small factors.
+		{12157665459056928801, map[uint64]int{3: 40}},
+		{1000000007 * 1000000007, map[uint64]int{1000000007: 2}},
+		{1031 * 1031 * 1031 * 1031 * 1031 * 1031, map[uint64]int{1031: 6}},
+		// Semiprimes with large factors, left for Pollard's rho.
+		{4294967291 * 4294967279, map[uint64]int{4294967291: 1, 4294967279: 1}},
+		{998244353 * 1000000007, map[uint64]int{998244353: 1, 1000000007: 1}},
+		{2 * 3 * 1000000007 * 1031, map[uint64]int{2: 1, 3: 1, 1031: 1, 1000000007: 1}},
+		{18446744073709551557, map[uint64]int{18446744073709551557: 1}},
+		{math.MaxUint64, map[uint64]int{3: 1, 5: 1, 17: 1, 257: 1, 641: 1, 65537: 1, 6700417: 1}},
+	}
+	for _, tt := range tests {
+		got := factorize(t, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("Factor(%d) = %v, want %v", tt.n, got, tt.want)
+			continue
+		}
+		for p, m := range tt.want {
+			if got[p] != m {
+				t.Errorf("Factor(%d) = %v, want %v", tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// The factors of random numbers multiply back to them.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := rng.Uint64() >> uint(rng.Intn(40))
+		product := new(big.Int).SetInt64(1)
+		for p, m := range factorize(t, n) {
+			product.Mul(product, new(big.Int).Exp(new(big.Int).SetUint64(p), big.NewInt(int64(m)), nil))
+		}
+		if n > 1 && (!product.IsUint64() || product.Uint64() != n) {
+			t.Errorf("Factor(%d): factors multiply to %v", n, product)
+		}
+	}
+}
+
+func TestFactorStops(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := Factor(testContext(t), 2*3*5*7, func(uint64, int) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("emit failing: got %v after %d calls, want the emit error after 1", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Factor(ctx, 4294967291*4294967279, func(uint64, int) error { return nil }); err != context.Canceled {
+		t.Errorf("cancelled context: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFactorBig(t *testing.T) {
+	p36 := big.NewInt(68719476731)
+	p40 := big.NewInt(1099511627689)
+	m61 := new(big.Int).SetUint64(2305843009213693951)
+	m89, _ := new(big.Int).SetString("618970019642690137449562111", 10) // 2^89 - 1
+	mul := func(xs ...*big.Int) *big.Int {
+		r := big.NewInt(1)
+		for _, x := range xs {
+			r.Mul(r, x)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name string
+		n    *big.Int
+		want map[string]int
+	}{
+		{"64-bit", big.NewInt(1 << 40), map[string]int{"2": 40}},
+		{"small factors over 64 bits", mul(big.NewInt(1<<62), big.NewInt(3*3*1021)), map[string]int{"2": 62, "3": 2, "1021": 1}},
+		{"semiprime", mul(p36, p40), map[string]int{"68719476731": 1, "1099511627689": 1}},
+		{"prime power", mul(m61, m61, m61), map[string]int{"2305843009213693951": 3}},
+		{"large prime", m89, map[string]int{"618970019642690137449562111": 1}},
+		{"mixed", mul(big.NewInt(6), p36, p36, m89), map[string]int{"2": 1, "3": 1, "68719476731": 2, "618970019642690137449562111": 1}},
+	}
+	for _, tt := range tests {
+		got := map[string]int{}
+		product := big.NewInt(1)
+		err := FactorBig(testContext(t), tt.n, func(p *big.Int, m int) error {
+			if !p.ProbablyPrime(20) {
+				t.Errorf("%s: emitted composite %v", tt.name, p)
+			}
+			got[p.String()] += m
+			product.Mul(product, new(big.Int).Exp(p, big.NewInt(int64(m)), nil))
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if product.Cmp(tt.n) != 0 {
+			t.Errorf("%s: factors multiply to %v, want %v", tt.name, product, tt.n)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for p, m := range tt.want {
+			if got[p] != m {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package linalg implements the dense vector and matrix operations of the
+// calculator. Matrices are row-major slices of rows of equal length.
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrSingular is returned by Solve when the matrix has no inverse.
+var ErrSingular = errors.New("matrix is singular")
+
+// Dot returns the dot product of two vectors of the same length.
+func Dot(a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("vectors have different lengths %d and %d", len(a), len(b))
+	}
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum, nil
+}
+
+// Dims returns the number of rows and columns of m, or an error if its rows
+// differ in length.
+func Dims(m [][]float64) (rows, cols int, err error) {
+	if len(m) == 0 {
+		return 0, 0, nil
+	}
+	cols = len(m[0])
+	for i, row := range m {
+		if len(row) != cols {
+			return 0, 0, fmt.Errorf("row %d has %d columns, row 0 has %d", i, len(row), cols)
+		}
+	}
+	return len(m), cols, nil
+}
+
+// Multiply returns the matrix product a×b. The columns of a must match the
+// rows of b.
+func Multiply(a, b [][]float64) ([][]float64, error) {
+	n, k, err := Dims(a)
+	if err != nil {
+		return nil, err
+	}
+	kb, m, err := Dims(b)
+	if err != nil {
+		return nil, err
+	}
+	if k != kb {
+		return nil, fmt.Errorf("cannot multiply %dx%d by %dx%d", n, k, kb, m)
+	}
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, m)
+		for p := 0; p < k; p++ {
+			aip := a[i][p]
+			for j := 0; j < m; j++ {
+				c[i][j] += aip * b[p][j]
+			}
+		}
+	}
+	return c, nil
+}
+
+// Determinant returns the determinant of the square matrix a. The
+// determinant of the empty matrix is 1.
+func Determinant(a [][]float64) (float64, error) {
+	f, err := factorize(a)
+	if err != nil {
+		return 0, err
+	}
+	if f.singular {
+		return 0, nil
+	}
+	det := float64(f.sign)
+	for i := range f.lu {
+		det *= f.lu[i][i]
+	}
+	return det, nil
+}
+
+// Solve returns x with a·x = b for the square matrix a. It returns
+// ErrSingular when a is singular or too close to it for a meaningful result.
+func Solve(a [][]float64, b []float64) ([]float64, error) {
+	f, err := factorize(a)
+	if err != nil {
+		return nil, err
+	}
+	n := len(f.lu)
+	if len(b) != n {
+		return nil, fmt.Errorf("right-hand side has %d values for %d equations", len(b), n)
+	}
+	if f.singular {
+		return nil, ErrSingular
+	}
+
+	// Forward substitution with the unit lower triangle, then backward
+	// substitution with the upper one.
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[f.perm[i]]
+		for j := 0; j < i; j++ {
+			sum -= f.lu[i][j] * x[j]
+		}
+		x[i] = sum
+	}
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= f.lu[i][j] * x[j]
+		}
+		x[i] = sum / f.lu[i][i]
+	}
+	return x, nil
+}
+
+// lu is an LU decomposition with partial pivoting: row perm[i] of the
+// original matrix is row i of L×U.
+type lu struct {
+	lu       [][]float64
+	perm     []int
+	sign     int
+	singular bool
+}
+
+func factorize(a [][]float64) (*lu, error) {
+	n, cols, err := Dims(a)
+	if err != nil {
+		return nil, err
+	}
+	if n != cols {
+		return nil, fmt.Errorf("matrix is %dx%d, not square", n, cols)
+	}
+
+	f := &lu{lu: make([][]float64, n), perm: make([]int, n), sign: 1}
+	scale := 0.0
+	for i := range a {
+		f.lu[i] = append([]float64(nil), a[i]...)
+		f.perm[i] = i
+		for _, v := range a[i] {
+			scale = math.Max(scale, math.Abs(v))
+		}
+	}
+	// Pivots this small relative to the entries are rounding noise.
+	tolerance := scale * float64(n) * 0x1p-52
+
+	m := f.lu
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(m[i][k]) > math.Abs(m[p][k]) {
+				p = i
+			}
+		}
+		if math.Abs(m[p][k]) <= tolerance {
+			f.singular = true
+			return f, nil
+		}
+		if p != k {
+			m[p], m[k] = m[k], m[p]
+			f.perm[p], f.perm[k] = f.perm[k], f.perm[p]
+			f.sign = -f.sign
+		}
+		for i := k + 1; i < n; i++ {
+			m[i][k] /= m[k][k]
+			for j := k + 1; j < n; j++ {
+				m[i][j] -= m[i][k] * m[k][j]
+			}
+		}
+	}
+	return f, nil
+}
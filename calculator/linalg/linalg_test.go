@@ -0,0 +1,205 @@
+package linalg
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// maxDimension matches the largest matrices the calculator server accepts.
+const maxDimension = 500
+
+func TestDot(t *testing.T) {
+	if got, err := Dot([]float64{1, 2, 3}, []float64{4, -5, 6}); err != nil || got != 12 {
+		t.Errorf("Dot = %v, %v, want 12", got, err)
+	}
+	if got, err := Dot(nil, nil); err != nil || got != 0 {
+		t.Errorf("Dot of empty vectors = %v, %v, want 0", got, err)
+	}
+	if _, err := Dot([]float64{1}, []float64{1, 2}); err == nil {
+		t.Errorf("Dot of different lengths: got no error")
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	got, err := Multiply([][]float64{{1, 2, 3}, {4, 5, 6}}, [][]float64{{7, 8}, {9, 10}, {11, 12}})
+	if want := [][]float64{{58, 64}, {139, 154}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Multiply = %v, %v, want %v", got, err, want)
+	}
+	if _, err := Multiply([][]float64{{1, 2}}, [][]float64{{1, 2}}); err == nil {
+		t.Errorf("Multiply 1x2 by 1x2: got no error")
+	}
+	if _, err := Multiply([][]float64{{1, 2}, {3}}, [][]float64{{1}, {2}}); err == nil {
+		t.Errorf("Multiply of a ragged matrix: got no error")
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [][]float64
+		want float64
+	}{
+		{"empty", nil, 1},
+		{"1x1", [][]float64{{-3}}, -3},
+		{"2x2", [][]float64{{1, 2}, {3, 4}}, -2},
+		// Pivoting swaps the rows, which flips the sign back.
+		{"zero leading entry", [][]float64{{0, 1}, {1, 0}}, -1},
+		{"3x3", [][]float64{{2, -3, 1}, {2, 0, -1}, {1, 4, 5}}, 49},
+		{"rank 2", [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, 0},
+		{"zero column", [][]float64{{1, 0}, {2, 0}}, 0},
+	}
+	for _, tt := range tests {
+		got, err := Determinant(tt.a)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: Determinant = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if _, err := Determinant([][]float64{{1, 2}}); err == nil {
+		t.Errorf("Determinant of a 1x2 matrix: got no error")
+	}
+}
+
+func TestSolve(t *testing.T) {
+	x, err := Solve([][]float64{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}}, []float64{8, -11, -3})
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	for i, want := range []float64{2, 3, -1} {
+		if math.Abs(x[i]-want) > 1e-12 {
+			t.Errorf("Solve = %v, want [2 3 -1]", x)
+			break
+		}
+	}
+
+	if _, err := Solve([][]float64{{1, 2}, {2, 4}}, []float64{1, 2}); err != ErrSingular {
+		t.Errorf("Solve of a singular matrix: got %v, want ErrSingular", err)
+	}
+	if _, err := Solve([][]float64{{1, 0}, {0, 1}}, []float64{1}); err == nil {
+		t.Errorf("Solve with too short a right-hand side: got no error")
+	}
+	if _, err := Solve([][]float64{{1, 0}}, []float64{1}); err == nil {
+		t.Errorf("Solve of a 1x2 matrix: got no error")
+	}
+}
+
+// randomMatrix returns an n×n matrix of entries in [-1, 1) times scale.
+func randomMatrix(rng *rand.Rand, n int, scale float64) [][]float64 {
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n)
+		for j := range a[i] {
+			a[i][j] = (2*rng.Float64() - 1) * scale
+		}
+	}
+	return a
+}
+
+// residual returns the largest |a·x - b|, relative to the largest |b|.
+func residual(a [][]float64, x, b []float64) float64 {
+	worst, norm := 0.0, 0.0
+	for i := range a {
+		ax, _ := Dot(a[i], x)
+		worst = math.Max(worst, math.Abs(ax-b[i]))
+		norm = math.Max(norm, math.Abs(b[i]))
+	}
+	return worst / norm
+}
+
+func TestSolveLarge(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	want := make([]float64, maxDimension)
+	for i := range want {
+		want[i] = 2*rng.Float64() - 1
+	}
+
+	// Singularity is judged relative to the entries, so neither tiny nor
+	// huge matrices are mistaken for singular ones.
+	for _, scale := range []float64{1, 1e-150, 1e150} {
+		a := randomMatrix(rng, maxDimension, scale)
+		b := make([]float64, maxDimension)
+		for i := range a {
+			b[i], _ = Dot(a[i], want)
+		}
+		x, err := Solve(a, b)
+		if err != nil {
+			t.Errorf("scale %g: %v", scale, err)
+			continue
+		}
+		if r := residual(a, x, b); r > 1e-10 {
+			t.Errorf("scale %g: residual %g", scale, r)
+		}
+	}
+}
+
+func TestSingularLarge(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name     string
+		modify   func(a [][]float64)
+		singular bool
+	}{
+		{
+			name:     "repeated row",
+			modify:   func(a [][]float64) { copy(a[maxDimension-1], a[0]) },
+			singular: true,
+		},
+		{
+			name: "zero column",
+			modify: func(a [][]float64) {
+				for i := range a {
+					a[i][maxDimension/2] = 0
+				}
+			},
+			singular: true,
+		},
+		{
+			// A row off another by less than rounding is singular in
+			// floating point.
+			name: "row within rounding of another",
+			modify: func(a [][]float64) {
+				for j := range a[0] {
+					a[maxDimension-1][j] = a[0][j] * (1 + 1e-17)
+				}
+			},
+			singular: true,
+		},
+		{
+			// Ill-conditioned, but far from rounding noise.
+			name: "row close to another",
+			modify: func(a [][]float64) {
+				for j := range a[0] {
+					a[maxDimension-1][j] = a[0][j] + 1e-8*(2*rng.Float64()-1)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		a := randomMatrix(rng, maxDimension, 1)
+		tt.modify(a)
+		b := make([]float64, maxDimension)
+		for i := range b {
+			b[i] = float64(i)
+		}
+
+		det, err := Determinant(a)
+		if err != nil {
+			t.Fatalf("%s: Determinant: %v", tt.name, err)
+		}
+		_, err = Solve(a, b)
+		if tt.singular {
+			if det != 0 || err != ErrSingular {
+				t.Errorf("%s: got determinant %g and %v, want 0 and ErrSingular", tt.name, det, err)
+			}
+			continue
+		}
+		if det == 0 || err != nil {
+			t.Errorf("%s: got determinant %g and %v, want a solution", tt.name, det, err)
+		}
+	}
+}
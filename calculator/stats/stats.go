@@ -0,0 +1,123 @@
+// Package stats computes descriptive statistics over a stream of numbers.
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Summary describes the numbers added to an Accumulator.
+type Summary struct {
+	Count int
+	Min   float64
+	Max   float64
+	Mean  float64
+	// Variance is the population variance.
+	Variance    float64
+	StdDev      float64
+	Median      float64
+	Percentiles []Percentile
+}
+
+// Percentile is the value below which Percentile percent of the numbers
+// fall.
+type Percentile struct {
+	Percentile float64
+	Value      float64
+}
+
+// Accumulator collects numbers. The mean and variance are kept with
+// Welford's algorithm; the numbers themselves are kept for the median and
+// percentiles, and only sorted when a Summary needs them.
+type Accumulator struct {
+	values []float64
+	// sorted is the length of the sorted prefix of values.
+	sorted int
+	mean   float64
+	m2     float64
+}
+
+// Add adds x.
+func (a *Accumulator) Add(x float64) {
+	a.values = append(a.values, x)
+
+	n := float64(len(a.values))
+	delta := x - a.mean
+	a.mean += delta / n
+	a.m2 += delta * (x - a.mean)
+}
+
+// Count returns how many numbers were added.
+func (a *Accumulator) Count() int {
+	return len(a.values)
+}
+
+// sort sorts the numbers added since the last call and merges them into the
+// sorted prefix from the back. The merge only moves the sorted numbers
+// greater than the smallest new one, so numbers that arrive in ascending
+// order are never moved.
+func (a *Accumulator) sort() {
+	if a.sorted == len(a.values) {
+		return
+	}
+	added := append([]float64(nil), a.values[a.sorted:]...)
+	sort.Float64s(added)
+
+	i, j := a.sorted-1, len(added)-1
+	for k := len(a.values) - 1; j >= 0; k-- {
+		if i >= 0 && a.values[i] > added[j] {
+			a.values[k] = a.values[i]
+			i--
+		} else {
+			a.values[k] = added[j]
+			j--
+		}
+	}
+	a.sorted = len(a.values)
+}
+
+// Summary returns the statistics of the numbers added so far, with the
+// requested percentiles (each between 0 and 100). It must not be called on
+// an empty Accumulator. It takes O(n + k log k) time for k numbers added
+// since the last Summary, since those have to be sorted in.
+func (a *Accumulator) Summary(percentiles []float64) Summary {
+	a.sort()
+	n := len(a.values)
+	variance := a.m2 / float64(n)
+	s := Summary{
+		Count:    n,
+		Min:      a.values[0],
+		Max:      a.values[n-1],
+		Mean:     a.mean,
+		Variance: variance,
+		StdDev:   math.Sqrt(variance),
+		Median:   PercentileOf(a.values, 50),
+	}
+	for _, p := range percentiles {
+		s.Percentiles = append(s.Percentiles, Percentile{Percentile: p, Value: PercentileOf(a.values, p)})
+	}
+	return s
+}
+
+// PercentileOf returns the p-th percentile of the non-empty sorted slice,
+// interpolating linearly between the two closest ranks.
+func PercentileOf(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// ValidatePercentiles checks that every percentile is between 0 and 100.
+func ValidatePercentiles(percentiles []float64) error {
+	for _, p := range percentiles {
+		if math.IsNaN(p) || p < 0 || p > 100 {
+			return fmt.Errorf("percentile %v is not between 0 and 100", p)
+		}
+	}
+	return nil
+}
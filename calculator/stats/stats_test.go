@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		numbers     []float64
+		percentiles []float64
+		want        Summary
+	}{
+		{
+			name:    "single number",
+			numbers: []float64{7},
+			want:    Summary{Count: 1, Min: 7, Max: 7, Mean: 7, Median: 7},
+		},
+		{
+			name:    "even count",
+			numbers: []float64{4, 2},
+			want:    Summary{Count: 2, Min: 2, Max: 4, Mean: 3, Variance: 1, StdDev: 1, Median: 3},
+		},
+		{
+			name:    "all negative",
+			numbers: []float64{-1, -5, -3},
+			want:    Summary{Count: 3, Min: -5, Max: -1, Mean: -3, Variance: 8.0 / 3, StdDev: math.Sqrt(8.0 / 3), Median: -3},
+		},
+		{
+			name:        "interpolated percentiles",
+			numbers:     []float64{5, 1, 4, 2, 3},
+			percentiles: []float64{0, 10, 25, 90, 100},
+			want: Summary{
+				Count: 5, Min: 1, Max: 5, Mean: 3, Variance: 2, StdDev: math.Sqrt(2), Median: 3,
+				Percentiles: []Percentile{{0, 1}, {10, 1.4}, {25, 2}, {90, 4.6}, {100, 5}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var a Accumulator
+		for _, x := range tt.numbers {
+			a.Add(x)
+		}
+		if got := a.Summary(tt.percentiles); !summaryEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func summaryEqual(a, b Summary) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) <= 1e-9*math.Max(1, math.Abs(y)) }
+	if a.Count != b.Count || a.Min != b.Min || a.Max != b.Max || !near(a.Mean, b.Mean) ||
+		!near(a.Variance, b.Variance) || !near(a.StdDev, b.StdDev) || !near(a.Median, b.Median) ||
+		len(a.Percentiles) != len(b.Percentiles) {
+		return false
+	}
+	for i := range a.Percentiles {
+		if a.Percentiles[i].Percentile != b.Percentiles[i].Percentile || !near(a.Percentiles[i].Value, b.Percentiles[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSummaryInterleaved summarizes after batches of every size, so numbers
+// are sorted in both before and among those already sorted.
+func TestSummaryInterleaved(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var a Accumulator
+	var all []float64
+	for batch := 1; batch <= 50; batch++ {
+		for i := 0; i < batch; i++ {
+			x := math.Round(rng.NormFloat64() * 100)
+			a.Add(x)
+			all = append(all, x)
+		}
+		want := append([]float64(nil), all...)
+		sort.Float64s(want)
+
+		s := a.Summary([]float64{1, 99})
+		if s.Count != len(want) || s.Min != want[0] || s.Max != want[len(want)-1] ||
+			s.Median != PercentileOf(want, 50) || s.Percentiles[0].Value != PercentileOf(want, 1) {
+			t.Fatalf("after %d numbers: got %+v", len(want), s)
+		}
+		if !reflect.DeepEqual(a.values, want) {
+			t.Fatalf("after %d numbers: values not sorted", len(want))
+		}
+	}
+}
+
+// TestAddLinear checks that adding stays cheap: a million numbers in
+// descending order, the worst case of a sorted insert, take well under a
+// second.
+func TestAddLinear(t *testing.T) {
+	start := time.Now()
+	var a Accumulator
+	const n = 1 << 20
+	for i := n; i > 0; i-- {
+		a.Add(float64(i))
+	}
+	s := a.Summary(nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("%d numbers took %v", n, elapsed)
+	}
+	if s.Count != n || s.Min != 1 || s.Max != n || s.Median != (n+1)/2.0 {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestValidatePercentiles(t *testing.T) {
+	for _, ps := range [][]float64{nil, {0}, {50, 99.9, 100}} {
+		if err := ValidatePercentiles(ps); err != nil {
+			t.Errorf("ValidatePercentiles(%v): %v", ps, err)
+		}
+	}
+	for _, ps := range [][]float64{{-1}, {100.1}, {50, math.NaN()}, {math.Inf(1)}} {
+		if err := ValidatePercentiles(ps); err == nil {
+			t.Errorf("ValidatePercentiles(%v): got no error", ps)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package window aggregates a stream of numbers over count or time windows.
+//
+// A window of size n starting at every slide values (or a time window of
+// length d starting every slide) tumbles when slide equals its size and
+// slides when slide is smaller. Only windows that received values are
+// reported.
+package window
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Aggregation selects what is computed over a window.
+type Aggregation int
+
+// Supported aggregations.
+const (
+	Max Aggregation = iota + 1
+	Min
+	Sum
+	Mean
+)
+
+// MaxOverlap bounds how many windows a single value may fall into, i.e.
+// size / slide, as every value updates each of them.
+const MaxOverlap = 1024
+
+// Result is the aggregate of one window.
+type Result struct {
+	Value float64
+	Count int
+	// First and Last are the stream positions of the first and last value
+	// of a count window.
+	First, Last int64
+	// Start and End bound a time window.
+	Start, End time.Time
+}
+
+type aggregate struct {
+	count    int
+	sum      float64
+	min, max float64
+}
+
+func (a *aggregate) add(x float64) {
+	if a.count == 0 || x < a.min {
+		a.min = x
+	}
+	if a.count == 0 || x > a.max {
+		a.max = x
+	}
+	a.count++
+	a.sum += x
+}
+
+func (a *aggregate) value(agg Aggregation) float64 {
+	switch agg {
+	case Max:
+		return a.max
+	case Min:
+		return a.min
+	case Sum:
+		return a.sum
+	case Mean:
+		return a.sum / float64(a.count)
+	}
+	return math.NaN()
+}
+
+func checkAggregation(agg Aggregation) error {
+	if agg < Max || agg > Mean {
+		return fmt.Errorf("unknown aggregation %d", agg)
+	}
+	return nil
+}
+
+// Count aggregates over windows of a fixed number of values.
+type Count struct {
+	agg         Aggregation
+	size, slide int64
+	next        int64 // position of the next value
+	open        []*countWindow
+}
+
+type countWindow struct {
+	first int64
+	aggregate
+}
+
+// NewCount returns count windows of size values starting every slide
+// values; slide 0 means tumbling windows.
+func NewCount(agg Aggregation, size, slide int) (*Count, error) {
+	if err := checkAggregation(agg); err != nil {
+		return nil, err
+	}
+	if slide == 0 {
+		slide = size
+	}
+	if size <= 0 || slide < 0 {
+		return nil, fmt.Errorf("window size must be positive and slide not negative, got %d and %d", size, slide)
+	}
+	if size/slide > MaxOverlap {
+		return nil, fmt.Errorf("a window of %d values sliding by %d overlaps more than %d others", size, slide, MaxOverlap)
+	}
+	return &Count{agg: agg, size: int64(size), slide: int64(slide)}, nil
+}
+
+// Add adds the next value and returns the windows it completed.
+func (c *Count) Add(x float64) []Result {
+	pos := c.next
+	c.next++
+	if pos%c.slide == 0 {
+		c.open = append(c.open, &countWindow{first: pos})
+	}
+
+	var done []Result
+	keep := c.open[:0]
+	for _, w := range c.open {
+		w.add(x)
+		if int64(w.count) == c.size {
+			done = append(done, c.result(w))
+			continue
+		}
+		keep = append(keep, w)
+	}
+	c.open = keep
+	return done
+}
+
+// Flush returns the windows that are still open, with fewer values than
+// their size.
+func (c *Count) Flush() []Result {
+	var done []Result
+	for _, w := range c.open {
+		if w.count > 0 {
+			done = append(done, c.result(w))
+		}
+	}
+	c.open = nil
+	return done
+}
+
+func (c *Count) result(w *countWindow) Result {
+	return Result{
+		Value: w.value(c.agg),
+		Count: w.count,
+		First: w.first,
+		Last:  w.first + int64(w.count) - 1,
+	}
+}
+
+// Time aggregates over windows of a fixed duration, aligned to the time
+// the windows were created.
+type Time struct {
+	agg         Aggregation
+	size, slide time.Duration
+	origin      time.Time
+	// open holds the windows that received values, by ascending index.
+	open []*timeWindow
+}
+
+type timeWindow struct {
+	index int64 // the window covers [origin+index*slide, +size)
+	aggregate
+}
+
+// NewTime returns time windows of length size starting every slide from
+// origin; slide 0 means tumbling windows.
+func NewTime(agg Aggregation, size, slide time.Duration, origin time.Time) (*Time, error) {
+	if err := checkAggregation(agg); err != nil {
+		return nil, err
+	}
+	if slide == 0 {
+		slide = size
+	}
+	if size <= 0 || slide < 0 {
+		return nil, fmt.Errorf("window duration must be positive and slide not negative, got %v and %v", size, slide)
+	}
+	if size/slide > MaxOverlap {
+		return nil, fmt.Errorf("a window of %v sliding by %v overlaps more than %d others", size, slide, MaxOverlap)
+	}
+	return &Time{agg: agg, size: size, slide: slide, origin: origin}, nil
+}
+
+// Add adds x arriving at now and returns the windows that ended by then.
+func (t *Time) Add(now time.Time, x float64) []Result {
+	done := t.Advance(now)
+
+	d := now.Sub(t.origin)
+	if d < 0 {
+		return done
+	}
+	// Windows k with k*slide <= d < k*slide+size.
+	first := int64(0)
+	if d >= t.size {
+		first = int64((d-t.size)/t.slide) + 1
+	}
+	last := int64(d / t.slide)
+
+	i := 0
+	for k := first; k <= last; k++ {
+		for i < len(t.open) && t.open[i].index < k {
+			i++
+		}
+		if i == len(t.open) || t.open[i].index != k {
+			// Indexes only grow with time, so new windows go last.
+			t.open = append(t.open, &timeWindow{index: k})
+		}
+		t.open[i].add(x)
+		i++
+	}
+	return done
+}
+
+// Advance returns the windows that ended by now.
+func (t *Time) Advance(now time.Time) []Result {
+	var done []Result
+	for len(t.open) > 0 && !t.end(t.open[0]).After(now) {
+		done = append(done, t.result(t.open[0]))
+		t.open = t.open[1:]
+	}
+	return done
+}
+
+// Deadline returns when the earliest open window ends, or false when no
+// window is open.
+func (t *Time) Deadline() (time.Time, bool) {
+	if len(t.open) == 0 {
+		return time.Time{}, false
+	}
+	return t.end(t.open[0]), true
+}
+
+// Flush returns the windows that are still open, before they ended.
+func (t *Time) Flush() []Result {
+	var done []Result
+	for _, w := range t.open {
+		done = append(done, t.result(w))
+	}
+	t.open = nil
+	return done
+}
+
+func (t *Time) start(w *timeWindow) time.Time {
+	return t.origin.Add(time.Duration(w.index) * t.slide)
+}
+
+func (t *Time) end(w *timeWindow) time.Time {
+	return t.start(w).Add(t.size)
+}
+
+func (t *Time) result(w *timeWindow) Result {
+	return Result{
+		Value: w.value(t.agg),
+		Count: w.count,
+		Start: t.start(w),
+		End:   t.end(w),
+	}
+}
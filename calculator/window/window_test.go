@@ -0,0 +1,181 @@
+package window
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAggregations(t *testing.T) {
+	for agg, want := range map[Aggregation]float64{Max: 4, Min: -2, Sum: 6, Mean: 1.5} {
+		c, err := NewCount(agg, 4, 0)
+		if err != nil {
+			t.Fatalf("NewCount(%d): %v", agg, err)
+		}
+		var got []Result
+		for _, x := range []float64{1, 4, -2, 3} {
+			got = append(got, c.Add(x)...)
+		}
+		if len(got) != 1 || got[0].Value != want {
+			t.Errorf("aggregation %d: got %v, want one window of %v", agg, got, want)
+		}
+	}
+}
+
+// countWindows adds 0, 1, 2... n-1 and returns the windows completed by Add
+// and then by Flush.
+func countWindows(t *testing.T, size, slide, n int) (added, flushed []Result) {
+	t.Helper()
+	c, err := NewCount(Sum, size, slide)
+	if err != nil {
+		t.Fatalf("NewCount(%d, %d): %v", size, slide, err)
+	}
+	for i := 0; i < n; i++ {
+		added = append(added, c.Add(float64(i))...)
+	}
+	return added, c.Flush()
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name               string
+		size, slide, n     int
+		wantAdded, wantEnd []Result
+	}{
+		{
+			name: "tumbling, exact multiple", size: 3, n: 6,
+			wantAdded: []Result{{Value: 3, Count: 3, First: 0, Last: 2}, {Value: 12, Count: 3, First: 3, Last: 5}},
+		},
+		{
+			name: "tumbling, partial last window", size: 3, n: 7,
+			wantAdded: []Result{{Value: 3, Count: 3, First: 0, Last: 2}, {Value: 12, Count: 3, First: 3, Last: 5}},
+			wantEnd:   []Result{{Value: 6, Count: 1, First: 6, Last: 6}},
+		},
+		{
+			name: "sliding", size: 3, slide: 2, n: 6,
+			wantAdded: []Result{{Value: 3, Count: 3, First: 0, Last: 2}, {Value: 9, Count: 3, First: 2, Last: 4}},
+			wantEnd:   []Result{{Value: 9, Count: 2, First: 4, Last: 5}},
+		},
+		{
+			// Values between windows fall into none.
+			name: "hopping", size: 2, slide: 3, n: 8,
+			wantAdded: []Result{{Value: 1, Count: 2, First: 0, Last: 1}, {Value: 7, Count: 2, First: 3, Last: 4}, {Value: 13, Count: 2, First: 6, Last: 7}},
+		},
+		{
+			name: "size 1", size: 1, n: 2,
+			wantAdded: []Result{{Value: 0, Count: 1, First: 0, Last: 0}, {Value: 1, Count: 1, First: 1, Last: 1}},
+		},
+		{
+			name: "nothing added", size: 3, n: 0,
+		},
+	}
+	for _, tt := range tests {
+		added, flushed := countWindows(t, tt.size, tt.slide, tt.n)
+		if !reflect.DeepEqual(added, tt.wantAdded) {
+			t.Errorf("%s: Add completed %v, want %v", tt.name, added, tt.wantAdded)
+		}
+		if !reflect.DeepEqual(flushed, tt.wantEnd) {
+			t.Errorf("%s: Flush returned %v, want %v", tt.name, flushed, tt.wantEnd)
+		}
+	}
+}
+
+func TestCountLimits(t *testing.T) {
+	for _, tt := range []struct{ size, slide int }{{0, 0}, {-1, 1}, {3, -1}, {MaxOverlap + 1, 1}} {
+		if _, err := NewCount(Sum, tt.size, tt.slide); err == nil {
+			t.Errorf("NewCount(%d, %d): got no error", tt.size, tt.slide)
+		}
+	}
+	if _, err := NewCount(Sum, MaxOverlap, 1); err != nil {
+		t.Errorf("NewCount(%d, 1): %v", MaxOverlap, err)
+	}
+	if _, err := NewCount(Mean+1, 1, 0); err == nil {
+		t.Errorf("NewCount with an unknown aggregation: got no error")
+	}
+}
+
+func TestTimeTumbling(t *testing.T) {
+	origin := time.Unix(1000, 0)
+	at := func(ms float64) time.Time { return origin.Add(time.Duration(ms * float64(time.Millisecond))) }
+	w, err := NewTime(Sum, 10*time.Millisecond, 0, origin)
+	if err != nil {
+		t.Fatalf("NewTime: %v", err)
+	}
+	if _, ok := w.Deadline(); ok {
+		t.Errorf("Deadline before any value: got one")
+	}
+
+	// Values before the origin fall into no window.
+	if got := w.Add(at(-1), 100); got != nil {
+		t.Errorf("Add before origin: got %v", got)
+	}
+	w.Add(at(0), 1)
+	w.Add(at(9.999), 2)
+	if deadline, ok := w.Deadline(); !ok || !deadline.Equal(at(10)) {
+		t.Errorf("Deadline = %v, %v, want %v", deadline, ok, at(10))
+	}
+	if got := w.Advance(at(9.999)); got != nil {
+		t.Errorf("Advance before the end: got %v", got)
+	}
+
+	// A window ends at its end time, and a value then opens the next one.
+	want := []Result{{Value: 3, Count: 2, Start: at(0), End: at(10)}}
+	if got := w.Add(at(10), 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("Add at the end of a window: got %v, want %v", got, want)
+	}
+
+	// Windows without values are skipped.
+	want = []Result{{Value: 4, Count: 1, Start: at(10), End: at(20)}}
+	if got := w.Add(at(35), 8); !reflect.DeepEqual(got, want) {
+		t.Errorf("Add after a gap: got %v, want %v", got, want)
+	}
+	if deadline, _ := w.Deadline(); !deadline.Equal(at(40)) {
+		t.Errorf("Deadline after a gap = %v, want %v", deadline, at(40))
+	}
+
+	want = []Result{{Value: 8, Count: 1, Start: at(30), End: at(40)}}
+	if got := w.Flush(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flush: got %v, want %v", got, want)
+	}
+	if _, ok := w.Deadline(); ok {
+		t.Errorf("Deadline after Flush: got one")
+	}
+}
+
+func TestTimeSliding(t *testing.T) {
+	origin := time.Unix(1000, 0)
+	at := func(ms int) time.Time { return origin.Add(time.Duration(ms) * time.Millisecond) }
+	w, err := NewTime(Sum, 10*time.Millisecond, 5*time.Millisecond, origin)
+	if err != nil {
+		t.Fatalf("NewTime: %v", err)
+	}
+
+	// At 7ms, a value is in the windows starting at 0 and 5ms.
+	w.Add(at(7), 1)
+	// At 12ms, the first window has ended, and the value is in the windows
+	// starting at 5 and 10ms.
+	want := []Result{{Value: 1, Count: 1, Start: at(0), End: at(10)}}
+	if got := w.Add(at(12), 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Add at 12ms: got %v, want %v", got, want)
+	}
+
+	// Advancing past several ends returns the windows in order.
+	want = []Result{
+		{Value: 3, Count: 2, Start: at(5), End: at(15)},
+		{Value: 2, Count: 1, Start: at(10), End: at(20)},
+	}
+	if got := w.Advance(at(25)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Advance to 25ms: got %v, want %v", got, want)
+	}
+	if got := w.Flush(); got != nil {
+		t.Errorf("Flush: got %v, want nothing", got)
+	}
+}
+
+func TestTimeLimits(t *testing.T) {
+	for _, tt := range []struct{ size, slide time.Duration }{{0, 0}, {time.Second, -1}, {time.Second, time.Second / (MaxOverlap + 1)}} {
+		if _, err := NewTime(Sum, tt.size, tt.slide, time.Now()); err == nil {
+			t.Errorf("NewTime(%v, %v): got no error", tt.size, tt.slide)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package client dials the course services with the settings every
+// application should share: retries with exponential backoff for idempotent
+// methods, default deadlines, keepalive, transport security, request IDs,
+// message compression and round robin load balancing across healthy
+// backends. The per-service packages (greetclient, calculatorclient and
+// blogclient) wrap it with the method configuration of their service.
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/health" // client side health checking
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+)
+
+// DefaultKeepalive pings the server after 30s without activity on an open
+// stream, and drops the connection if the ping is not answered within 10s.
+var DefaultKeepalive = keepalive.ClientParameters{
+	Time:    30 * time.Second,
+	Timeout: 10 * time.Second,
+}
+
+// Service describes the service a connection is for.
+type Service struct {
+	// Name is the fully qualified service name, also used as the service
+	// name in health checks.
+	Name string
+	// Methods is the default method configuration of the service.
+	Methods []MethodConfig
+}
+
+type options struct {
+	creds       credentials.TransportCredentials
+	token       string
+	clientID    string
+	keepalive   keepalive.ClientParameters
+	methods     []MethodConfig
+	balancer    string
+	healthCheck bool
+	resolvers   []resolver.Builder
+	compressor  string
+	dialOpts    []grpc.DialOption
+}
+
+// Option configures Dial.
+type Option func(*options) error
+
+// WithTLS verifies the server against the CA certificate in caFile.
+// serverName, when set, overrides the host name checked in the certificate.
+func WithTLS(caFile, serverName string) Option {
+	return func(o *options) error {
+		creds, err := credentials.NewClientTLSFromFile(caFile, serverName)
+		if err != nil {
+			return fmt.Errorf("loading CA trust certificate: %v", err)
+		}
+		o.creds = creds
+		return nil
+	}
+}
+
+// WithTransportCredentials uses creds instead of a plaintext connection.
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *options) error {
+		o.creds = creds
+		return nil
+	}
+}
+
+// WithToken sends token as a bearer token in the authorization metadata of
+// every call.
+func WithToken(token string) Option {
+	return func(o *options) error {
+		o.token = token
+		return nil
+	}
+}
+
+// WithClientID names the application to the servers, which mention it in
+// rate limit errors. The limits themselves apply per peer address, or per
+// client certificate, whatever the client ID.
+func WithClientID(id string) Option {
+	return func(o *options) error {
+		o.clientID = id
+		return nil
+	}
+}
+
+// WithKeepalive replaces DefaultKeepalive.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(o *options) error {
+		o.keepalive = params
+		return nil
+	}
+}
+
+// WithMethodConfig adds method configurations. They take precedence over
+// the service defaults for the same method.
+func WithMethodConfig(methods ...MethodConfig) Option {
+	return func(o *options) error {
+		o.methods = append(o.methods, methods...)
+		return nil
+	}
+}
+
+// WithBalancer selects the load balancing policy, "round_robin" unless set.
+// "pick_first" sends every call to the first reachable address.
+func WithBalancer(name string) Option {
+	return func(o *options) error {
+		o.balancer = name
+		return nil
+	}
+}
+
+// WithoutHealthCheck stops watching the standard health service of each
+// backend. By default a backend reporting anything but SERVING for the
+// service is taken out of rotation until it recovers.
+func WithoutHealthCheck() Option {
+	return func(o *options) error {
+		o.healthCheck = false
+		return nil
+	}
+}
+
+// WithResolvers makes extra resolvers available to this connection only,
+// such as a manual resolver in tests.
+func WithResolvers(builders ...resolver.Builder) Option {
+	return func(o *options) error {
+		o.resolvers = append(o.resolvers, builders...)
+		return nil
+	}
+}
+
+// WithCompression compresses the messages of every call with the named
+// compressor, "gzip" or "snappy", and asks the server to compress its
+// responses the same way. Servers only compress messages above their
+// minimum size. UseCompression overrides it for a single call.
+func WithCompression(name string) Option {
+	return func(o *options) error {
+		if !compression.IsValid(name) {
+			return fmt.Errorf("unknown compressor %q, want one of %v", name, compression.Names)
+		}
+		o.compressor = name
+		return nil
+	}
+}
+
+// UseCompression compresses the messages of one call with the named
+// compressor, or sends them uncompressed with "identity".
+func UseCompression(name string) grpc.CallOption {
+	return grpc.UseCompressor(name)
+}
+
+// WithDialOptions passes extra options straight to grpc.Dial.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) error {
+		o.dialOpts = append(o.dialOpts, opts...)
+		return nil
+	}
+}
+
+// Dial connects to target, which may be a single address, a name for a
+// resolver such as "dns:///calculator:50051", or several addresses joined by
+// Target. The connection is established in the background, so Dial only
+// fails on invalid options.
+func Dial(target string, svc Service, opts ...Option) (*grpc.ClientConn, error) {
+	o := options{keepalive: DefaultKeepalive, balancer: "round_robin", healthCheck: true}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	svc.Methods = append(append([]MethodConfig(nil), svc.Methods...), o.methods...)
+	methods, err := newMethodConfigs(svc.Methods)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := serviceConfig(svc, o.balancer, o.healthCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithKeepaliveParams(o.keepalive),
+		grpc.WithDefaultServiceConfig(sc),
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor(), retryInterceptor(methods)),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+	}
+	if o.creds != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(o.creds))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	if o.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: o.token, secure: o.creds != nil}))
+	}
+	if o.clientID != "" {
+		dialOpts = append(dialOpts,
+			grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(withClientID(ctx, o.clientID), method, req, reply, cc, opts...)
+			}),
+			grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(withClientID(ctx, o.clientID), desc, cc, method, opts...)
+			}),
+		)
+	}
+	if o.compressor != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(o.compressor)))
+	}
+	if len(o.resolvers) > 0 {
+		dialOpts = append(dialOpts, grpc.WithResolvers(o.resolvers...))
+	}
+	dialOpts = append(dialOpts, o.dialOpts...)
+
+	return grpc.Dial(target, dialOpts...)
+}
+
+// ContextWithRequestID returns ctx sending id as the request ID of the
+// calls made with it. Every call sends a request ID, which servers log and
+// echo in the x-request-id header and trailer; without ContextWithRequestID
+// it is the ID of the call being served, when ctx comes from one, or a
+// random one.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return requestid.NewOutgoingContext(ctx, id)
+}
+
+// clientIDKey is the metadata key servers read the client ID from.
+const clientIDKey = "x-client-id"
+
+func withClientID(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, clientIDKey, id)
+}
+
+// RetryDelay returns the delay a server asked for before err's call is
+// retried, as when a rate limit was hit. Retry policies already wait
+// that long; RetryDelay is for calls retried by hand, such as streams.
+func RetryDelay(err error) (time.Duration, bool) {
+	return rpcerr.Decode(err).RetryDelay()
+}
+
+// Reason returns the reason code the server gave for err, such as
+// "BLOG_NOT_FOUND", or "" for none. Reasons are stable, unlike messages, so
+// they are what callers should branch on beyond the status code.
+func Reason(err error) string {
+	return rpcerr.Reason(err)
+}
+
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+// RequireTransportSecurity only insists on TLS when the connection uses it,
+// so tokens can still be passed to a local plaintext server.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.secure
+}
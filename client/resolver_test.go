@@ -0,0 +1,115 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/greet/greetclient"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// namedGreeter answers every Greet with the name of its backend.
+type namedGreeter struct {
+	greetpb.UnimplementedGreetServiceServer
+	name string
+}
+
+func (g namedGreeter) Greet(context.Context, *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	return &greetpb.GreetResponse{Result: g.name}, nil
+}
+
+// backends starts a greet server with a health service for each name.
+func backends(t *testing.T, names ...string) (map[string]*bufconn.Listener, map[string]*health.Server) {
+	listeners := map[string]*bufconn.Listener{}
+	healths := map[string]*health.Server{}
+	for _, name := range names {
+		name := name
+		hs := health.NewServer()
+		hs.SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_SERVING)
+		listeners[name] = servertest.Serve(t, func(s *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(s, namedGreeter{name: name})
+			healthpb.RegisterHealthServer(s, hs)
+		})
+		healths[name] = hs
+	}
+	return listeners, healths
+}
+
+// served makes n calls and counts the backends that answered them.
+func served(t *testing.T, c greetpb.GreetServiceClient, n int) map[string]int {
+	t.Helper()
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		res, err := c.Greet(testContext(t), &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}})
+		if err != nil {
+			t.Fatalf("Greet: %v", err)
+		}
+		counts[res.GetResult()]++
+	}
+	return counts
+}
+
+// waitServed calls until n calls in a row are spread exactly across want.
+func waitServed(t *testing.T, c greetpb.GreetServiceClient, n int, want ...string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		counts := served(t, c, n)
+		ok := len(counts) == len(want)
+		for _, name := range want {
+			ok = ok && counts[name] > 0
+		}
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("calls went to %v, want them spread across %v", counts, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	listeners, healths := backends(t, "a", "b")
+	c, err := greetclient.New(client.Target("a", "b"), withListeners(listeners))
+	if err != nil {
+		t.Fatalf("greetclient.New: %v", err)
+	}
+	defer c.Close()
+
+	// Round robin alternates once both backends are ready.
+	waitServed(t, c, 2, "a", "b")
+	if counts := served(t, c, 20); counts["a"] != 10 || counts["b"] != 10 {
+		t.Errorf("20 calls went to %v, want 10 to each backend", counts)
+	}
+
+	healths["b"].SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_NOT_SERVING)
+	waitServed(t, c, 10, "a")
+	if counts := served(t, c, 20); counts["b"] != 0 {
+		t.Errorf("with b NOT_SERVING, calls went to %v, want none to b", counts)
+	}
+
+	healths["b"].SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_SERVING)
+	waitServed(t, c, 2, "a", "b")
+}
+
+func TestStaticTarget(t *testing.T) {
+	if got, want := client.Target("localhost:50051"), "localhost:50051"; got != want {
+		t.Errorf("Target of one address: got %q, want %q", got, want)
+	}
+	if got, want := client.Target("a:1", "b:2"), "static:///a:1,b:2"; got != want {
+		t.Errorf("Target of two addresses: got %q, want %q", got, want)
+	}
+
+	if conn, err := client.Dial("static:///", client.Service{Name: "greet.GreetService"}); err == nil {
+		conn.Close()
+		t.Errorf("Dial of a static target without addresses: got no error")
+	}
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validate applies the rules gRPC has for retry policies in service configs.
+func (p *RetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 2:
+		return errors.New("retry policy: MaxAttempts must be at least 2")
+	case p.InitialBackoff <= 0 || p.MaxBackoff <= 0:
+		return errors.New("retry policy: backoffs must be positive")
+	case p.BackoffMultiplier <= 0:
+		return errors.New("retry policy: BackoffMultiplier must be positive")
+	case len(p.RetryableCodes) == 0:
+		return errors.New("retry policy: no RetryableCodes")
+	}
+	return nil
+}
+
+func (p *RetryPolicy) retryable(code codes.Code) bool {
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the attempt after attempt n.
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	max := float64(p.InitialBackoff) * math.Pow(p.BackoffMultiplier, float64(n-1))
+	if max > float64(p.MaxBackoff) {
+		max = float64(p.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+// methodConfigs finds the configuration of a method the way gRPC does for
+// service configs: the config naming the method, else the one naming its
+// service, the later one winning when several name the same.
+type methodConfigs map[string]MethodConfig
+
+func newMethodConfigs(methods []MethodConfig) (methodConfigs, error) {
+	mc := methodConfigs{}
+	for _, m := range methods {
+		if m.Retry != nil {
+			if err := m.Retry.validate(); err != nil {
+				return nil, err
+			}
+		}
+		mc["/"+m.Service+"/"+m.Method] = m
+	}
+	return mc, nil
+}
+
+func (mc methodConfigs) lookup(fullMethod string) (MethodConfig, bool) {
+	if m, ok := mc[fullMethod]; ok {
+		return m, true
+	}
+	m, ok := mc[fullMethod[:strings.LastIndex(fullMethod, "/")+1]]
+	return m, ok
+}
+
+// retryInterceptor retries unary calls under their method's retry policy.
+// The policies are kept out of the service config, which gRPC would apply
+// on top of this interceptor, so the client applies them itself: that way
+// a delay the server asks for replaces the backoff, and the method timeout
+// bounds the call as a whole, attempts included.
+func retryInterceptor(methods methodConfigs) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		m, _ := methods.lookup(method)
+		p := m.Retry
+		if p == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if m.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+			defer cancel()
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= p.MaxAttempts || !p.retryable(status.Code(err)) {
+				return err
+			}
+			delay, ok := rpcerr.Decode(err).RetryDelay()
+			if !ok {
+				delay = p.backoff(attempt)
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// MethodConfig sets the default deadline and retry policy of one method, or
+// of every method of a service when Method is empty.
+type MethodConfig struct {
+	// Service is the fully qualified service name, e.g.
+	// "calculator.CalculatorService".
+	Service string
+	Method  string
+	// Timeout is applied to calls whose context has no earlier deadline.
+	// Zero leaves calls without a deadline.
+	Timeout time.Duration
+	// Retry enables retries of unary calls. Only set it on idempotent
+	// methods: a retried call may already have run on the server.
+	Retry *RetryPolicy
+}
+
+// RetryPolicy retries a failed call with exponential backoff. The delay
+// before attempt n is random in [0, min(InitialBackoff*Multiplier^(n-1),
+// MaxBackoff)].
+type RetryPolicy struct {
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	RetryableCodes    []codes.Code
+}
+
+// DefaultRetryPolicy retries calls that failed because the server could not
+// be reached or rate limited them, up to four attempts in total. Rate
+// limited calls are retried after the delay the server asks for.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       4,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        2 * time.Second,
+	BackoffMultiplier: 2,
+	RetryableCodes:    []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+}
+
+type jsonServiceConfig struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+	HealthCheckConfig   *jsonHealthCheck      `json:"healthCheckConfig,omitempty"`
+	MethodConfig        []jsonMethodConfig    `json:"methodConfig,omitempty"`
+}
+
+type jsonHealthCheck struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type jsonName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type jsonMethodConfig struct {
+	Name    []jsonName `json:"name"`
+	Timeout string     `json:"timeout,omitempty"`
+}
+
+// serviceConfig renders the gRPC service config for svc in JSON. When two
+// method configs name the same method the later one wins. Retry policies are
+// left out: gRPC applies those of service configs by default, and retrying
+// on top of retryInterceptor would multiply the attempts.
+func serviceConfig(svc Service, balancer string, healthCheck bool) (string, error) {
+	var sc jsonServiceConfig
+	if balancer != "" {
+		sc.LoadBalancingConfig = []map[string]struct{}{{balancer: {}}}
+	}
+	if healthCheck {
+		sc.HealthCheckConfig = &jsonHealthCheck{ServiceName: svc.Name}
+	}
+
+	index := map[jsonName]int{}
+	for _, m := range svc.Methods {
+		name := jsonName{Service: m.Service, Method: m.Method}
+		mc := jsonMethodConfig{Name: []jsonName{name}}
+		if m.Timeout > 0 {
+			mc.Timeout = duration(m.Timeout)
+		}
+
+		if i, ok := index[name]; ok {
+			sc.MethodConfig[i] = mc
+			continue
+		}
+		index[name] = len(sc.MethodConfig)
+		sc.MethodConfig = append(sc.MethodConfig, mc)
+	}
+
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// duration formats d the way service config JSON expects, e.g. "0.1s".
+func duration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,218 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogclient"
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorclient"
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/greet/greetclient"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// withListeners dials the in-memory listeners by address, so targets such
+// as client.Target("a", "b") reach the servers behind them.
+func withListeners(listeners map[string]*bufconn.Listener) client.Option {
+	return client.WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return listeners[addr].DialContext(ctx)
+	}))
+}
+
+// TestServiceConfig checks that gRPC accepts the service config of every
+// service, which Dial rejects when it does not parse.
+func TestServiceConfig(t *testing.T) {
+	services := []client.Service{
+		{Name: "greet.GreetService", Methods: greetclient.MethodConfigs()},
+		{Name: "calculator.CalculatorService", Methods: calculatorclient.MethodConfigs()},
+		{Name: "blog.BlogService", Methods: blogclient.MethodConfigs()},
+	}
+	for _, svc := range services {
+		for _, opts := range [][]client.Option{
+			nil,
+			{client.WithBalancer("pick_first"), client.WithoutHealthCheck()},
+		} {
+			conn, err := client.Dial("localhost:50051", svc, opts...)
+			if err != nil {
+				t.Errorf("%s: Dial: %v", svc.Name, err)
+				continue
+			}
+			conn.Close()
+		}
+	}
+
+	// An invalid policy is refused, so the check above is not vacuous.
+	bad := client.DefaultRetryPolicy
+	bad.MaxAttempts = 1
+	svc := client.Service{Name: "blog.BlogService", Methods: []client.MethodConfig{{Service: "blog.BlogService", Retry: &bad}}}
+	if conn, err := client.Dial("localhost:50051", svc); err == nil {
+		conn.Close()
+		t.Errorf("Dial with a single attempt retry policy: got no error, want it rejected")
+	}
+}
+
+// flakyBlog fails its first calls with code and counts the attempts of each
+// method.
+type flakyBlog struct {
+	blogpb.UnimplementedBlogServiceServer
+	fail int
+	code codes.Code
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (f *flakyBlog) attempt(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts[method]++
+	if f.attempts[method] <= f.fail {
+		return status.Errorf(f.code, "attempt %d fails", f.attempts[method])
+	}
+	return nil
+}
+
+func (f *flakyBlog) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRequest) (*blogpb.ReadBlogResponse, error) {
+	if err := f.attempt("ReadBlog"); err != nil {
+		return nil, err
+	}
+	return &blogpb.ReadBlogResponse{Blog: &blogpb.Blog{Id: req.GetBlogId()}}, nil
+}
+
+func (f *flakyBlog) CreateBlog(ctx context.Context, req *blogpb.CreateBlogRequest) (*blogpb.CreateBlogResponse, error) {
+	if err := f.attempt("CreateBlog"); err != nil {
+		return nil, err
+	}
+	return &blogpb.CreateBlogResponse{Blog: req.GetBlog()}, nil
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		fail         int
+		code         codes.Code
+		call         func(context.Context, blogpb.BlogServiceClient) error
+		method       string
+		wantCode     codes.Code
+		wantAttempts int
+	}{
+		{
+			name: "idempotent recovers", fail: 2, code: codes.Unavailable,
+			call:   readBlog,
+			method: "ReadBlog", wantCode: codes.OK, wantAttempts: 3,
+		},
+		{
+			name: "idempotent gives up", fail: 10, code: codes.Unavailable,
+			call:   readBlog,
+			method: "ReadBlog", wantCode: codes.Unavailable, wantAttempts: client.DefaultRetryPolicy.MaxAttempts,
+		},
+		{
+			name: "idempotent not retryable", fail: 1, code: codes.NotFound,
+			call:   readBlog,
+			method: "ReadBlog", wantCode: codes.NotFound, wantAttempts: 1,
+		},
+		{
+			name: "not idempotent", fail: 1, code: codes.Unavailable,
+			call: func(ctx context.Context, c blogpb.BlogServiceClient) error {
+				_, err := c.CreateBlog(ctx, &blogpb.CreateBlogRequest{Blog: &blogpb.Blog{Title: "Notes"}})
+				return err
+			},
+			method: "CreateBlog", wantCode: codes.Unavailable, wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &flakyBlog{fail: tt.fail, code: tt.code, attempts: map[string]int{}}
+			lis := servertest.Serve(t, func(s *grpc.Server) { blogpb.RegisterBlogServiceServer(s, srv) })
+			c, err := blogclient.New("blog", withListeners(map[string]*bufconn.Listener{"blog": lis}))
+			if err != nil {
+				t.Fatalf("blogclient.New: %v", err)
+			}
+			defer c.Close()
+
+			if err := tt.call(testContext(t), c); status.Code(err) != tt.wantCode {
+				t.Errorf("got %v, want %v", err, tt.wantCode)
+			}
+			srv.mu.Lock()
+			defer srv.mu.Unlock()
+			if got := srv.attempts[tt.method]; got != tt.wantAttempts {
+				t.Errorf("%s attempts: got %d, want %d", tt.method, got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func readBlog(ctx context.Context, c blogpb.BlogServiceClient) error {
+	_, err := c.ReadBlog(ctx, &blogpb.ReadBlogRequest{BlogId: "5f8f8c44b54764421b7156c9"})
+	return err
+}
+
+// TestRetryAttempts checks that a call failing every attempt is tried
+// MaxAttempts times in all. gRPC applies the retry policies of service
+// configs by default, so a policy left in the service config would retry
+// each attempt of the client's own retries, MaxAttempts² times in all.
+//
+// gRPC only retries responses without headers, which the servers' request
+// IDs rule out, so the test serves a bare server.
+func TestRetryAttempts(t *testing.T) {
+	policy := client.RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        time.Millisecond,
+		BackoffMultiplier: 1,
+		RetryableCodes:    []codes.Code{codes.Unavailable},
+	}
+	readBlogRetried := client.WithMethodConfig(client.MethodConfig{Service: "blog.BlogService", Method: "ReadBlog", Retry: &policy})
+	// The same policy in a service config, as gRPC takes it.
+	inServiceConfig := client.WithDialOptions(grpc.WithDefaultServiceConfig(`{"methodConfig": [{
+		"name": [{"service": "blog.BlogService", "method": "ReadBlog"}],
+		"retryPolicy": {"maxAttempts": 3, "initialBackoff": "0.001s", "maxBackoff": "0.001s", "backoffMultiplier": 1, "retryableStatusCodes": ["UNAVAILABLE"]}
+	}]}`))
+
+	tests := []struct {
+		name         string
+		opts         []client.Option
+		wantAttempts int
+	}{
+		{"client policy", []client.Option{readBlogRetried}, 3},
+		{"in the service config too", []client.Option{readBlogRetried, inServiceConfig}, 9},
+	}
+	for _, tt := range tests {
+		srv := &flakyBlog{fail: 100, code: codes.Unavailable, attempts: map[string]int{}}
+		lis := bufconn.Listen(1 << 20)
+		s := grpc.NewServer()
+		blogpb.RegisterBlogServiceServer(s, srv)
+		go s.Serve(lis)
+
+		opts := append([]client.Option{withListeners(map[string]*bufconn.Listener{"blog": lis}), client.WithoutHealthCheck()}, tt.opts...)
+		c, err := blogclient.New("blog", opts...)
+		if err != nil {
+			t.Fatalf("%s: blogclient.New: %v", tt.name, err)
+		}
+		if err := readBlog(testContext(t), c); status.Code(err) != codes.Unavailable {
+			t.Errorf("%s: got %v, want %v", tt.name, err, codes.Unavailable)
+		}
+		c.Close()
+		s.Stop()
+
+		srv.mu.Lock()
+		if got := srv.attempts["ReadBlog"]; got != tt.wantAttempts {
+			t.Errorf("%s: ReadBlog attempts: got %d, want %d", tt.name, got, tt.wantAttempts)
+		}
+		srv.mu.Unlock()
+	}
+}
@@ -1,4 +1,4 @@
 #!/bin/bash
-protoc greet/greetpb/greet.proto --go_out=plugins=grpc:.
-protoc calculator/calculatorpb/calculator.proto --go_out=plugins=grpc:.
-protoc blog/blogpb/blog.proto --go_out=plugins=grpc:.
\ No newline at end of file
+# Regenerates the protobuf, gRPC and mock code. Needs protoc on the PATH; see
+# internal/cmd/protogen.
+exec go run ./internal/cmd/protogen "$@"
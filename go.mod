@@ -3,8 +3,13 @@ module github.com/pandadragoon/grpc-go-course
 go 1.16
 
 require (
-	github.com/golang/protobuf v1.4.3 // indirect
-	go.mongodb.org/mongo-driver v1.5.0 // indirect
-	google.golang.org/grpc v1.36.0 // indirect
-	google.golang.org/protobuf v1.25.0 // indirect
+	github.com/golang/protobuf v1.5.0
+	github.com/golang/snappy v0.0.1
+	github.com/google/go-cmp v0.5.8 // indirect
+	go.mongodb.org/mongo-driver v1.5.0
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.41.0
+	google.golang.org/grpc/cmd/protoc-gen-go-grpc v1.2.0
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v2 v2.4.0
 )
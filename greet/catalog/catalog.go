@@ -0,0 +1,133 @@
+// Package catalog holds the localized greetings of the greet service and
+// picks the language to greet in from the caller's preferences.
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Style is the register of a greeting.
+type Style int
+
+// Supported styles.
+const (
+	Informal Style = iota
+	Formal
+)
+
+// Default is the language used when none of the preferred ones is in the
+// catalog.
+const Default = "en"
+
+// messages are the format strings of one language. Greetings take the first
+// name as %[1]s and the last name as %[2]s; times takes a greeting and a
+// count.
+type messages struct {
+	informal string
+	formal   string
+	times    string
+}
+
+// catalog is keyed by lowercase BCP 47 tag. Regional entries only need to
+// exist where they differ from the language.
+var catalog = map[string]messages{
+	"en":    {informal: "Hello %[1]s", formal: "Good day, %[1]s %[2]s", times: "%s %d times"},
+	"en-au": {informal: "G'day %[1]s", formal: "Good day, %[1]s %[2]s", times: "%s %d times"},
+	"de":    {informal: "Hallo %[1]s", formal: "Guten Tag, %[1]s %[2]s", times: "%s %d Mal"},
+	"de-ch": {informal: "Grüezi %[1]s", formal: "Grüezi, %[1]s %[2]s", times: "%s %d Mal"},
+	"es":    {informal: "Hola %[1]s", formal: "Buenos días, %[1]s %[2]s", times: "%s %d veces"},
+	"fr":    {informal: "Salut %[1]s", formal: "Bonjour, %[1]s %[2]s", times: "%s %d fois"},
+	"it":    {informal: "Ciao %[1]s", formal: "Buongiorno, %[1]s %[2]s", times: "%s %d volte"},
+	"pt":    {informal: "Olá %[1]s", formal: "Bom dia, %[1]s %[2]s", times: "%s %d vezes"},
+	"pt-br": {informal: "Oi %[1]s", formal: "Bom dia, %[1]s %[2]s", times: "%s %d vezes"},
+	// Family name first, with the honorific after it.
+	"ja": {informal: "こんにちは、%[1]sさん", formal: "%[2]s様、こんにちは", times: "%s（%d回）"},
+}
+
+// Match returns the catalog language for the first of preferred (BCP 47
+// tags in order of preference) that is supported, trying each tag and then
+// its parents: "de-CH-1996" tries "de-ch-1996", "de-ch" and "de". It returns
+// Default when nothing matches.
+func Match(preferred ...string) string {
+	for _, tag := range preferred {
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+		for tag != "" {
+			if _, ok := catalog[tag]; ok {
+				return tag
+			}
+			i := strings.LastIndex(tag, "-")
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	return Default
+}
+
+// Greeting greets a person in the language returned by Match. A formal
+// greeting needs the last name and falls back to the informal one without
+// it; an informal one uses the last name when there is no first name.
+func Greeting(language string, style Style, firstName, lastName string) string {
+	m := lookup(language)
+	if style == Formal && lastName != "" {
+		// Collapse the gap left by a missing first name.
+		return strings.Join(strings.Fields(fmt.Sprintf(m.formal, firstName, lastName)), " ")
+	}
+	if firstName == "" {
+		firstName = lastName
+	}
+	return fmt.Sprintf(m.informal, firstName, lastName)
+}
+
+// Times appends the count of a repeated greeting.
+func Times(language, greeting string, n int) string {
+	return fmt.Sprintf(lookup(language).times, greeting, n)
+}
+
+func lookup(language string) messages {
+	if m, ok := catalog[language]; ok {
+		return m
+	}
+	return catalog[Default]
+}
+
+// ParseAcceptLanguage returns the tags of an Accept-Language header value,
+// such as "fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5", most preferred first. The
+// wildcard and tags with q=0 are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -1,244 +1,424 @@
 package main
 
 import (
-	"context"
+	"bufio"
+	"flag"
 	"fmt"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/status"
 	"io"
-	"log"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/pandadragoon/grpc-go-course/greet/greetclient"
 	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
-	"google.golang.org/grpc"
+	"github.com/pandadragoon/grpc-go-course/internal/cli"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+const program = "greet_client"
+
 func main() {
-	fmt.Println("Hello I'm a client")
-
-	tls := false
-	opts := grpc.WithInsecure()
-	if tls {
-		certFile := "ssl/ca.crt" // Certificate Authority Trust certificate
-		creds, sslErr := credentials.NewClientTLSFromFile(certFile, "")
-		if sslErr != nil {
-			log.Fatalf("Error while loading CA trust certificate: %v", sslErr)
-			return
+	cli.Main(program, []cli.Command{
+		{Name: "greet", Summary: "greet one person (Greet)", Run: runGreet},
+		{Name: "many", Summary: "receive a stream of greetings for one person (GreetManyTimes)", Run: runGreetManyTimes},
+		{Name: "long", Summary: "send several names and get one greeting back (LongGreet)", Run: runLongGreet},
+		{Name: "everyone", Summary: "greet names as they are sent, optionally typed interactively (GreetEveryone)", Run: runGreetEveryone},
+		{Name: "deadline", Summary: "greet one person on the slow endpoint (GreetWithDeadline)", Run: runGreetWithDeadline},
+	})
+}
+
+// options are the flags shared by every subcommand.
+type options struct {
+	conn   cli.ConnFlags
+	json   bool
+	locale string
+	formal bool
+}
+
+func newFlagSet(command string, timeout time.Duration, opts *options) *flag.FlagSet {
+	fs := cli.NewFlagSet(program, command)
+	opts.conn.Register(fs, timeout)
+	fs.BoolVar(&opts.json, "json", false, "print responses as JSON, one object per line")
+	fs.StringVar(&opts.locale, "locale", "", "language to greet in, such as de-CH; the server defaults to English")
+	fs.BoolVar(&opts.formal, "formal", false, "greet formally, by last name")
+	return fs
+}
+
+// localize sets the -locale and -formal choices on g.
+func (o *options) localize(g *greetpb.Greeting) *greetpb.Greeting {
+	g.Locale = o.locale
+	if o.formal {
+		g.Style = greetpb.Style_STYLE_FORMAL
+	}
+	return g
+}
+
+func dial(opts *options) (*greetclient.Client, error) {
+	return greetclient.New(opts.conn.Target(), opts.conn.ClientOptions()...)
+}
+
+// print writes res as a JSON line, or its result text in plain mode.
+func (o *options) print(res proto.Message, result string) error {
+	if o.json {
+		return cli.WriteJSONLine(os.Stdout, res)
+	}
+	_, err := fmt.Println(result)
+	return err
+}
+
+// nameList collects repeated -name flags.
+type nameList []string
+
+func (n *nameList) String() string { return strings.Join(*n, ", ") }
+
+func (n *nameList) Set(s string) error {
+	*n = append(*n, s)
+	return nil
+}
+
+// names holds the flags used to pick who gets greeted.
+type names struct {
+	list nameList
+	file string
+}
+
+func (n *names) register(fs *flag.FlagSet) {
+	fs.Var(&n.list, "name", "name to greet as \"First Last\" (repeatable)")
+	fs.StringVar(&n.file, "names-file", "", "file with one \"First Last\" name per line ('-' for stdin)")
+}
+
+// greetings returns the names from -name followed by those in -names-file.
+func (n *names) greetings() ([]*greetpb.Greeting, error) {
+	var all []*greetpb.Greeting
+	for _, name := range n.list {
+		all = append(all, parseName(name))
+	}
+
+	if n.file != "" {
+		r := os.Stdin
+		if n.file != "-" {
+			f, err := os.Open(n.file)
+			if err != nil {
+				return nil, fmt.Errorf("reading names: %v", err)
+			}
+			defer f.Close()
+			r = f
+		}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				all = append(all, parseName(line))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading names: %v", err)
 		}
-		opts = grpc.WithTransportCredentials(creds)
 	}
 
-	cc, err := grpc.Dial("localhost:50051", opts)
-	if err != nil {
-		log.Fatalf("could not connect: %v", err)
+	if len(all) == 0 {
+		return nil, cli.Usagef("no names given: use -name or -names-file")
 	}
-	defer cc.Close()
+	return all, nil
+}
+
+// parseName splits "First Last" on the first space; everything after it is
+// the last name.
+func parseName(s string) *greetpb.Greeting {
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 2)
+	g := &greetpb.Greeting{FirstName: fields[0]}
+	if len(fields) == 2 {
+		g.LastName = strings.TrimSpace(fields[1])
+	}
+	return g
+}
+
+// singleName handles the commands that greet exactly one person, given
+// either as -first/-last or as a single "First Last" argument.
+type singleName struct {
+	first string
+	last  string
+}
+
+func (s *singleName) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.first, "first", "", "first name")
+	fs.StringVar(&s.last, "last", "", "last name")
+}
 
-	c := greetpb.NewGreetServiceClient(cc)
-	//doUnary(c)
-	//doServerStreaming(c)
-	//doClientStreaming(c)
-	//doBiDiStreaming(c)
-	doUnaryWithDeadline(c, 5 * time.Second)
-	doUnaryWithDeadline(c, 1 * time.Second)
+func (s *singleName) greeting(fs *flag.FlagSet) (*greetpb.Greeting, error) {
+	switch {
+	case fs.NArg() > 1:
+		return nil, cli.Usagef("expected one name, got %d arguments", fs.NArg())
+	case fs.NArg() == 1 && s.first == "" && s.last == "":
+		return parseName(fs.Arg(0)), nil
+	case fs.NArg() == 1:
+		return nil, cli.Usagef("give the name either as an argument or with -first/-last, not both")
+	case s.first == "":
+		return nil, cli.Usagef("no name given: use -first or a \"First Last\" argument")
+	}
+	return &greetpb.Greeting{FirstName: s.first, LastName: s.last}, nil
 }
 
-func doUnary(c greetpb.GreetServiceClient){
-	fmt.Println("Starting to do a Unary RPC")
-	req := &greetpb.GreetRequest{
-		Greeting: &greetpb.Greeting{
-			FirstName: "Josh",
-			LastName: "Edwards",
-		},
+func runGreet(args []string) error {
+	var opts options
+	var name singleName
+	fs := newFlagSet("greet", 0, &opts)
+	name.register(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
 	}
-	res, err := c.Greet(context.Background(), req)
+	greeting, err := name.greeting(fs)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(&opts)
 	if err != nil {
-		log.Fatalf("Error while calling Greet RPC: %v", err)
+		return err
 	}
+	defer c.Close()
 
-	log.Printf("Response from Greet: %v", res.Result)
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: opts.localize(greeting)})
+	if err != nil {
+		return err
+	}
+	return opts.print(res, res.GetResult())
 }
 
-func doServerStreaming(c greetpb.GreetServiceClient){
-	fmt.Println("Starting to do a Server Streaming RPC")
-	req := &greetpb.GreetManyTimesRequest{
-		Greeting: &greetpb.Greeting{
-			FirstName: "Josh",
-			LastName: "Edwards",
-		},
+func runGreetManyTimes(args []string) error {
+	var opts options
+	var name singleName
+	fs := newFlagSet("many", 0, &opts)
+	name.register(fs)
+	count := fs.Int("count", 0, "number of greetings (0 for the server default of 10)")
+	interval := fs.Duration("interval", -1, "pause between greetings (negative for the server default of 500ms)")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
 	}
-	resStream, err := c.GreetManyTimes(context.Background(), req)
+	greeting, err := name.greeting(fs)
+	if err != nil {
+		return err
+	}
+	req := &greetpb.GreetManyTimesRequest{Greeting: opts.localize(greeting), Count: int32(*count)}
+	if *interval >= 0 {
+		req.Interval = durationpb.New(*interval)
+	}
+
+	c, err := dial(&opts)
 	if err != nil {
-		log.Fatalf("Error while calling GreetManyTimes RPC: %v", err)
+		return err
 	}
+	defer c.Close()
 
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.GreetManyTimes(ctx, req)
+	if err != nil {
+		return err
+	}
 	for {
-		msg, err := resStream.Recv()
+		res, err := stream.Recv()
 		if err == io.EOF {
-			break;
+			return nil
 		}
-
 		if err != nil {
-			log.Fatalf("Error while reading stream: %v", err)
+			return err
 		}
+		if err := opts.print(res, res.GetResult()); err != nil {
+			return err
+		}
+	}
+}
 
-		log.Printf("Response from GreetManyTimes: %v", msg)
-	}
-}
-
-func doClientStreaming(c greetpb.GreetServiceClient) {
-	fmt.Println("Starting to do a Client Streaming RPC")
-	requests := []*greetpb.LongGreetRequest{
-		&greetpb.LongGreetRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Josh",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.LongGreetRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jeremy",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.LongGreetRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jacob",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.LongGreetRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jacqueline",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.LongGreetRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jennifer",
-				LastName: "Edwards",
-			},
-		},
-	}
-
-	stream, err := c.LongGreet(context.Background())
+func runLongGreet(args []string) error {
+	var opts options
+	var who names
+	fs := newFlagSet("long", 0, &opts)
+	who.register(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Usagef("unexpected arguments: %v", fs.Args())
+	}
+	greetings, err := who.greetings()
 	if err != nil {
-		log.Fatalf("Error calling LongGreet: %v", err)
+		return err
 	}
 
-	for _, req := range requests {
-		fmt.Printf("Sending a request: %v \n", req)
-		stream.Send(req)
-		time.Sleep(500 * time.Millisecond)
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.LongGreet(ctx)
+	if err != nil {
+		return err
+	}
+	for _, g := range greetings {
+		if err := stream.Send(&greetpb.LongGreetRequest{Greeting: opts.localize(g)}); err != nil {
+			if err == io.EOF {
+				// The server ended the call early; CloseAndRecv reports why.
+				break
+			}
+			return err
+		}
 	}
 
 	res, err := stream.CloseAndRecv()
 	if err != nil {
-		log.Fatalf("Error receiving response from LongGreet: %v", err)
+		return err
 	}
-	fmt.Printf("LongGreetResponse %v", res)
+	return opts.print(res, res.GetResult())
 }
 
-func doBiDiStreaming(c greetpb.GreetServiceClient) {
-	fmt.Println("Starting to do a BiDi Streaming RPC")
+func runGreetEveryone(args []string) error {
+	var opts options
+	var who names
+	var interactive bool
+	var roomName string
+	fs := newFlagSet("everyone", 0, &opts)
+	who.register(fs)
+	fs.BoolVar(&interactive, "i", false, "interactive: send each line typed on stdin as a name")
+	fs.StringVar(&roomName, "room", "", "join this room, named after the first greeting, and receive every member's greetings")
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return cli.Usagef("unexpected arguments: %v", fs.Args())
+	}
 
-	stream, err := c.GreetEveryone(context.Background())
-	if err != nil {
-		log.Fatalf("Error calling GreetEveryone: %v", err)
-		return
-	}
-
-	waitc := make(chan struct{})
-	requests := []*greetpb.GreetEveryoneRequest{
-		&greetpb.GreetEveryoneRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Josh",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.GreetEveryoneRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jeremy",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.GreetEveryoneRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jacob",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.GreetEveryoneRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jacqueline",
-				LastName: "Edwards",
-			},
-		},
-		&greetpb.GreetEveryoneRequest{
-			Greeting: &greetpb.Greeting{
-				FirstName: "Jennifer",
-				LastName: "Edwards",
-			},
-		},
-	}
-
-	go func(){
-		for _, req := range requests {
-			fmt.Printf("sending message: %v \n", req)
-			stream.Send(req)
-			time.Sleep(500 * time.Millisecond)
+	var greetings []*greetpb.Greeting
+	if !interactive {
+		var err error
+		if greetings, err = who.greetings(); err != nil {
+			return err
 		}
-		stream.CloseSend()
-	}()
+	} else if len(who.list) > 0 || who.file != "" {
+		return cli.Usagef("-i reads names from stdin and cannot be combined with -name or -names-file")
+	}
+
+	c, err := dial(&opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	stream, err := c.GreetEveryone(ctx)
+	if err != nil {
+		return err
+	}
 
-	go func(){
+	// Replies are printed as they arrive, independently of sending.
+	var wg sync.WaitGroup
+	var recvErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
 		for {
 			res, err := stream.Recv()
 			if err == io.EOF {
-				break
+				return
 			}
 			if err != nil {
-				log.Printf("Error receiving response: %v", err)
-				break
+				recvErr = err
+				return
 			}
+			if res.GetDropped() > 0 && !opts.json {
+				fmt.Fprintf(os.Stderr, "(missed %d messages)\n", res.GetDropped())
+			}
+			if err := opts.print(res, res.GetResult()); err != nil {
+				recvErr = err
+				cancel()
+				return
+			}
+		}
+	}()
 
-			fmt.Printf("Received: %v \n", res.GetResult())
+	first := true
+	send := func(g *greetpb.Greeting) error {
+		req := &greetpb.GreetEveryoneRequest{Greeting: opts.localize(g)}
+		if first {
+			req.Room = roomName
+			first = false
+		}
+		err := stream.Send(req)
+		if err == io.EOF {
+			// The server ended the call; the receiver reports why.
+			return nil
 		}
+		return err
+	}
 
-		close(waitc)
-	}()
+	var sendErr error
+	if interactive {
+		fmt.Fprintln(os.Stderr, "Type a name per line, Ctrl-D to finish.")
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			if sendErr = send(parseName(line)); sendErr != nil {
+				break
+			}
+		}
+		if sendErr == nil {
+			sendErr = scanner.Err()
+		}
+	} else {
+		for _, g := range greetings {
+			if sendErr = send(g); sendErr != nil {
+				break
+			}
+		}
+	}
 
-	<- waitc
-}
+	if sendErr != nil {
+		cancel()
+	} else {
+		sendErr = stream.CloseSend()
+	}
+	wg.Wait()
 
-func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration)  {
-	fmt.Println("Starting to do a Unary RPC")
-	req := &greetpb.GreetWithDeadlineRequest{
-		Greeting: &greetpb.Greeting{
-			FirstName: "Josh",
-			LastName: "Edwards",
-		},
+	if sendErr != nil {
+		return sendErr
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	return recvErr
+}
 
-	res, err := c.GreetWithDeadline(ctx, req)
+func runGreetWithDeadline(args []string) error {
+	var opts options
+	var name singleName
+	fs := newFlagSet("deadline", 5*time.Second, &opts)
+	name.register(fs)
+	if err := cli.Parse(fs, args); err != nil {
+		return err
+	}
+	greeting, err := name.greeting(fs)
 	if err != nil {
-		statusErr, ok := status.FromError(err)
-
-		if ok {
-			if statusErr.Code() == codes.DeadlineExceeded {
-				log.Printf("Exceeded deadline : %v.", statusErr.Message())
-			} else {
-				log.Printf(" %v : %v.", statusErr.Code(), statusErr.Message())
-			}
+		return err
+	}
 
-			return
-		} else {
-			log.Fatalf("Error while calling GreetWithDeadline RPC: %v", err)
-		}
+	c, err := dial(&opts)
+	if err != nil {
+		return err
 	}
+	defer c.Close()
 
-	log.Printf("Response from GreetWithDeadline: %v", res.Result)
-}
\ No newline at end of file
+	ctx, cancel := opts.conn.Context()
+	defer cancel()
+	res, err := c.GreetWithDeadline(ctx, &greetpb.GreetWithDeadlineRequest{Greeting: opts.localize(greeting)})
+	if err != nil {
+		return err
+	}
+	return opts.print(res, res.GetResult())
+}
@@ -1,127 +1,77 @@
 package main
 
 import (
-	"context"
+	"flag"
 	"fmt"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/status"
-	"io"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"net"
-	"strconv"
 	"time"
 
 	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/greet/greetserver"
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/deadline"
+	"github.com/pandadragoon/grpc-go-course/internal/grpcinfra"
+	"github.com/pandadragoon/grpc-go-course/internal/ratelimit"
+	"github.com/pandadragoon/grpc-go-course/internal/recovery"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
 	"google.golang.org/grpc"
 )
 
-type server struct{}
-
-func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-	log.Printf("Greet function was invoked with %v\n", req)
-	first_name := req.GetGreeting().GetFirstName()
-	result := "Hello " + first_name
-	res := &greetpb.GreetResponse{
-		Result: result,
-	}
-
-	return res, nil
-}
-
-func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-	log.Printf("GreetManyTimes function was invoked with %v\n", req)
-	firstName := req.GetGreeting().GetFirstName()
-
-	for i := 0; i < 10; i++ {
-		result := "Hello " + firstName + " " + strconv.Itoa(i) + " times"
-		res := &greetpb.GreetManyTimesResponse {
-			Result: result,
-		}
-
-		stream.Send(res)
-		time.Sleep(500 * time.Millisecond)
-	}
-
-	return nil
-}
-
-func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-	log.Printf("LongGreet function was invoked with a streaming request")
-	result := ""
-
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return stream.SendAndClose(&greetpb.LongGreetResponse{
-				Result: result,
-			})
-		}
-		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
-		}
-
-		firstName := req.GetGreeting().GetFirstName()
-		result += "Hello " + firstName + "! "
-	}
-}
-
-func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-	for {
-		req, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		}
-		if err != nil {
-			log.Fatalf("Error while reading client stream: %v", err)
-			return err
-		}
-
-		firstName := req.GetGreeting().GetFirstName()
-		result := "Hello " + firstName + "!! "
-
-
-		err = stream.Send(&greetpb.GreetEveryoneResponse{Result: result})
-		if err != nil {
-			log.Fatalf("Error while sending client stream: %v", err)
-			return err
-		}
-	}
-}
-
-func (*server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
-	log.Printf("GreetWithDeadline function was invoked with %v\n", req)
-	for i := 0; i < 3; i++ {
-		if ctx.Err() == context.Canceled {
-			log.Println("Client cancelled request")
-			return nil, status.Error(codes.DeadlineExceeded, "the client cancelled the request")
-		}
-		time.Sleep(1 * time.Second)
+func main() {
+	addr := flag.String("addr", "0.0.0.0:50051", "address to listen on")
+	tls := flag.Bool("tls", false, "serve using TLS")
+	certFile := flag.String("cert", "ssl/server.crt", "TLS certificate file")
+	keyFile := flag.String("key", "ssl/server.pem", "TLS private key file")
+	limits := ratelimit.Config{
+		Default:    ratelimit.Limit{Rate: 100, Burst: 200},
+		MaxStreams: 32,
 	}
-	firstName := req.GetGreeting().GetFirstName()
-	result := "Hello " + firstName
-	res := &greetpb.GreetWithDeadlineResponse{
-		Result: result,
+	ratelimit.RegisterFlags(flag.CommandLine, &limits)
+	deadlines := deadline.Config{
+		Limits: deadline.Limits{Default: 10 * time.Second, Max: time.Minute},
+		Methods: map[string]deadline.Limits{
+			// Long running by design: bounded by count and interval, or by
+			// how long the client keeps talking.
+			"/greet.GreetService/GreetManyTimes": {},
+			"/greet.GreetService/GreetEveryone":  {},
+		},
 	}
+	deadline.RegisterFlags(flag.CommandLine, &deadlines)
+	compressionConfig := compression.Config{MinSize: compression.DefaultMinSize}
+	compression.RegisterFlags(flag.CommandLine, &compressionConfig)
+	flag.Parse()
+	compression.Configure(compressionConfig)
 
-	return res, nil
-}
-
-func main() {
 	fmt.Println("Hello world")
 
-	lis, err := net.Listen("tcp", "0.0.0.0:50051")
+	lis, err := net.Listen("tcp", *addr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	opts := []grpc.ServerOption{}
-	tls := false
-	if tls {
-		certFile := "ssl/server.crt"
-		keyFile := "ssl/server.pem"
-		creds, sslErr := credentials.NewServerTLSFromFile(certFile, keyFile)
+	opts := []grpc.ServerOption{
+		grpcinfra.KeepaliveEnforcement(),
+		deadline.ServerOption(deadlines),
+	}
+	limiter := ratelimit.New(limits)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor(),
+			recovery.UnaryServerInterceptor(),
+			limiter.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			requestid.StreamServerInterceptor(),
+			recovery.StreamServerInterceptor(),
+			limiter.StreamServerInterceptor(),
+		),
+	)
+	if *tls {
+		creds, sslErr := credentials.NewServerTLSFromFile(*certFile, *keyFile)
 		if sslErr != nil {
 			log.Fatalf("Failed loading certificates: %v", sslErr)
 			return
@@ -130,7 +80,11 @@ func main() {
 	}
 
 	s := grpc.NewServer(opts...)
-	greetpb.RegisterGreetServiceServer(s, &server{})
+	greetpb.RegisterGreetServiceServer(s, greetserver.New())
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
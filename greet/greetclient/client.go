@@ -0,0 +1,47 @@
+// Package greetclient is the client library for GreetService.
+package greetclient
+
+import (
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"google.golang.org/grpc"
+)
+
+const service = "greet.GreetService"
+
+// MethodConfigs returns the default method configuration: Greet is
+// idempotent and retried with a 10s deadline. GreetWithDeadline is left
+// without one since exercising deadlines is its purpose, and the streaming
+// methods run for as long as the caller keeps them open.
+func MethodConfigs() []client.MethodConfig {
+	retry := client.DefaultRetryPolicy
+	return []client.MethodConfig{
+		{Service: service, Method: "Greet", Timeout: 10 * time.Second, Retry: &retry},
+	}
+}
+
+// Client is a GreetServiceClient that owns its connection.
+type Client struct {
+	greetpb.GreetServiceClient
+	conn *grpc.ClientConn
+}
+
+// New dials target with MethodConfigs and opts. target is anything
+// client.Dial accepts, including client.Target of several replicas.
+func New(target string, opts ...client.Option) (*Client, error) {
+	conn, err := client.Dial(target, client.Service{Name: service, Methods: MethodConfigs()}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		GreetServiceClient: greetpb.NewGreetServiceClient(conn),
+		conn:               conn,
+	}, nil
+}
+
+// Close closes the underlying connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
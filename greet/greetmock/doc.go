@@ -0,0 +1,7 @@
+// Package greetmock provides mocks of the greet service's client and stream
+// interfaces, for testing code that uses greetpb without a server. Client
+// mocks record their calls and answer with programmable functions; stream
+// mocks replay programmed messages and record the ones sent.
+package greetmock
+
+//go:generate go run ../../internal/cmd/genmock -proto greet/greetpb/greet.proto -package greetmock -o mock.go
@@ -0,0 +1,90 @@
+package greetmock_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetmock"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"google.golang.org/grpc"
+)
+
+func ExampleGreetServiceClient() {
+	client := &greetmock.GreetServiceClient{
+		GreetFunc: func(ctx context.Context, in *greetpb.GreetRequest, opts ...grpc.CallOption) (*greetpb.GreetResponse, error) {
+			return &greetpb.GreetResponse{Result: "Hello " + in.GetGreeting().GetFirstName()}, nil
+		},
+	}
+
+	// Code under test takes a greetpb.GreetServiceClient.
+	var c greetpb.GreetServiceClient = client
+	res, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}})
+	fmt.Println(res.GetResult(), err)
+
+	// Methods without a function fail.
+	_, err = c.GreetWithDeadline(context.Background(), &greetpb.GreetWithDeadlineRequest{})
+	fmt.Println(err)
+
+	for _, call := range client.Calls() {
+		fmt.Println(call.Method)
+	}
+	// Output:
+	// Hello Ada <nil>
+	// rpc error: code = Unimplemented desc = mock: no response programmed for GreetWithDeadline
+	// Greet
+	// GreetWithDeadline
+}
+
+func Example_clientStream() {
+	client := &greetmock.GreetServiceClient{
+		GreetManyTimesFunc: func(ctx context.Context, in *greetpb.GreetManyTimesRequest, opts ...grpc.CallOption) (greetpb.GreetService_GreetManyTimesClient, error) {
+			return &greetmock.GreetService_GreetManyTimesClient{
+				Responses: []*greetpb.GreetManyTimesResponse{
+					{Result: "Hello Ada 0 times"},
+					{Result: "Hello Ada 1 times"},
+				},
+			}, nil
+		},
+	}
+
+	stream, _ := client.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Count: 2})
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		fmt.Println(res.GetResult())
+	}
+	// Output:
+	// Hello Ada 0 times
+	// Hello Ada 1 times
+}
+
+func Example_serverStream() {
+	// Server stream mocks drive a handler directly.
+	stream := &greetmock.GreetService_LongGreetServer{
+		Requests: []*greetpb.LongGreetRequest{
+			{Greeting: &greetpb.Greeting{FirstName: "Ada"}},
+			{Greeting: &greetpb.Greeting{FirstName: "Bob"}},
+		},
+	}
+	handler := func(stream greetpb.GreetService_LongGreetServer) error {
+		result := ""
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				return stream.SendAndClose(&greetpb.LongGreetResponse{Result: result})
+			}
+			if err != nil {
+				return err
+			}
+			result += "Hello " + req.GetGreeting().GetFirstName() + "! "
+		}
+	}
+
+	err := handler(stream)
+	fmt.Printf("%q %v\n", stream.Sent()[0].GetResult(), err)
+	// Output:
+	// "Hello Ada! Hello Bob! " <nil>
+}
@@ -0,0 +1,438 @@
+// Code generated by genmock. DO NOT EDIT.
+// source: greet/greetpb/greet.proto
+
+package greetmock
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a call recorded by a client mock.
+type Call struct {
+	// Method is the name of the method called, such as "Greet".
+	Method string
+	// Request is the request of a unary or server streaming call, and nil
+	// for calls that send a stream.
+	Request proto.Message
+	// Metadata is the outgoing metadata of the call's context.
+	Metadata metadata.MD
+}
+
+// calls records the calls of a client mock.
+type calls struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (c *calls) record(ctx context.Context, method string, req proto.Message) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Request: req, Metadata: md.Copy()})
+}
+
+// Calls returns the calls made so far, in order.
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+func unprogrammed(method string) error {
+	return status.Errorf(codes.Unimplemented, "mock: no response programmed for %s", method)
+}
+
+// GreetServiceClient is a mock greetpb.GreetServiceClient.
+//
+// Each method calls the function of the same name with a Func suffix, and
+// fails with UNIMPLEMENTED when that is nil. Calls are recorded either way.
+type GreetServiceClient struct {
+	calls
+
+	GreetFunc             func(ctx context.Context, in *greetpb.GreetRequest, opts ...grpc.CallOption) (*greetpb.GreetResponse, error)
+	GreetManyTimesFunc    func(ctx context.Context, in *greetpb.GreetManyTimesRequest, opts ...grpc.CallOption) (greetpb.GreetService_GreetManyTimesClient, error)
+	LongGreetFunc         func(ctx context.Context, opts ...grpc.CallOption) (greetpb.GreetService_LongGreetClient, error)
+	GreetEveryoneFunc     func(ctx context.Context, opts ...grpc.CallOption) (greetpb.GreetService_GreetEveryoneClient, error)
+	GreetWithDeadlineFunc func(ctx context.Context, in *greetpb.GreetWithDeadlineRequest, opts ...grpc.CallOption) (*greetpb.GreetWithDeadlineResponse, error)
+}
+
+var _ greetpb.GreetServiceClient = (*GreetServiceClient)(nil)
+
+func (m *GreetServiceClient) Greet(ctx context.Context, in *greetpb.GreetRequest, opts ...grpc.CallOption) (*greetpb.GreetResponse, error) {
+	m.record(ctx, "Greet", in)
+	if m.GreetFunc == nil {
+		return nil, unprogrammed("Greet")
+	}
+	return m.GreetFunc(ctx, in, opts...)
+}
+
+func (m *GreetServiceClient) GreetManyTimes(ctx context.Context, in *greetpb.GreetManyTimesRequest, opts ...grpc.CallOption) (greetpb.GreetService_GreetManyTimesClient, error) {
+	m.record(ctx, "GreetManyTimes", in)
+	if m.GreetManyTimesFunc == nil {
+		return nil, unprogrammed("GreetManyTimes")
+	}
+	return m.GreetManyTimesFunc(ctx, in, opts...)
+}
+
+func (m *GreetServiceClient) LongGreet(ctx context.Context, opts ...grpc.CallOption) (greetpb.GreetService_LongGreetClient, error) {
+	m.record(ctx, "LongGreet", nil)
+	if m.LongGreetFunc == nil {
+		return nil, unprogrammed("LongGreet")
+	}
+	return m.LongGreetFunc(ctx, opts...)
+}
+
+func (m *GreetServiceClient) GreetEveryone(ctx context.Context, opts ...grpc.CallOption) (greetpb.GreetService_GreetEveryoneClient, error) {
+	m.record(ctx, "GreetEveryone", nil)
+	if m.GreetEveryoneFunc == nil {
+		return nil, unprogrammed("GreetEveryone")
+	}
+	return m.GreetEveryoneFunc(ctx, opts...)
+}
+
+func (m *GreetServiceClient) GreetWithDeadline(ctx context.Context, in *greetpb.GreetWithDeadlineRequest, opts ...grpc.CallOption) (*greetpb.GreetWithDeadlineResponse, error) {
+	m.record(ctx, "GreetWithDeadline", in)
+	if m.GreetWithDeadlineFunc == nil {
+		return nil, unprogrammed("GreetWithDeadline")
+	}
+	return m.GreetWithDeadlineFunc(ctx, in, opts...)
+}
+
+// ClientStream implements grpc.ClientStream for the client stream mocks.
+// The messages of a stream go through its typed methods, so SendMsg and
+// RecvMsg fail.
+type ClientStream struct {
+	// Ctx is returned by Context; context.Background() when nil.
+	Ctx context.Context
+	// HeaderMD and HeaderErr are returned by Header.
+	HeaderMD  metadata.MD
+	HeaderErr error
+	// TrailerMD is returned by Trailer.
+	TrailerMD metadata.MD
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *ClientStream) Header() (metadata.MD, error) { return s.HeaderMD, s.HeaderErr }
+func (s *ClientStream) Trailer() metadata.MD         { return s.TrailerMD }
+
+func (s *ClientStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+// CloseSend marks the sending side closed.
+func (s *ClientStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// Closed reports whether the client closed its sending side.
+func (s *ClientStream) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *ClientStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ClientStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+
+// ServerStream implements grpc.ServerStream for the server stream mocks,
+// recording the metadata a handler sets.
+type ServerStream struct {
+	// Ctx is returned by Context; context.Background() when nil. Incoming
+	// metadata for the handler goes in it.
+	Ctx context.Context
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *ServerStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+func (s *ServerStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *ServerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *ServerStream) SetTrailer(md metadata.MD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// Header returns the header metadata the handler set or sent.
+func (s *ServerStream) Header() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Copy()
+}
+
+// Trailer returns the trailer metadata the handler set.
+func (s *ServerStream) Trailer() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trailer.Copy()
+}
+
+func (s *ServerStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ServerStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+
+// GreetService_GreetManyTimesClient is a mock greetpb.GreetService_GreetManyTimesClient.
+type GreetService_GreetManyTimesClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*greetpb.GreetManyTimesResponse
+	Err       error
+	received  int
+}
+
+var _ greetpb.GreetService_GreetManyTimesClient = (*GreetService_GreetManyTimesClient)(nil)
+
+func (s *GreetService_GreetManyTimesClient) Recv() (*greetpb.GreetManyTimesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// GreetService_GreetManyTimesServer is a mock greetpb.GreetService_GreetManyTimesServer.
+// Handlers can be called with it directly.
+type GreetService_GreetManyTimesServer struct {
+	ServerStream
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*greetpb.GreetManyTimesResponse
+}
+
+var _ greetpb.GreetService_GreetManyTimesServer = (*GreetService_GreetManyTimesServer)(nil)
+
+func (s *GreetService_GreetManyTimesServer) Send(m *greetpb.GreetManyTimesResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *GreetService_GreetManyTimesServer) Sent() []*greetpb.GreetManyTimesResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*greetpb.GreetManyTimesResponse(nil), s.sent...)
+}
+
+// GreetService_LongGreetClient is a mock greetpb.GreetService_LongGreetClient.
+type GreetService_LongGreetClient struct {
+	ClientStream
+	// Response and Err are returned by CloseAndRecv.
+	Response *greetpb.LongGreetResponse
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*greetpb.LongGreetRequest
+}
+
+var _ greetpb.GreetService_LongGreetClient = (*GreetService_LongGreetClient)(nil)
+
+func (s *GreetService_LongGreetClient) Send(m *greetpb.LongGreetRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *GreetService_LongGreetClient) Sent() []*greetpb.LongGreetRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*greetpb.LongGreetRequest(nil), s.sent...)
+}
+
+func (s *GreetService_LongGreetClient) CloseAndRecv() (*greetpb.LongGreetResponse, error) {
+	s.CloseSend()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return s.Response, nil
+}
+
+// GreetService_LongGreetServer is a mock greetpb.GreetService_LongGreetServer.
+// Handlers can be called with it directly.
+type GreetService_LongGreetServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*greetpb.LongGreetRequest
+	Err      error
+	// SendErr is returned by SendAndClose without recording the message.
+	SendErr error
+
+	sent     []*greetpb.LongGreetResponse
+	received int
+}
+
+var _ greetpb.GreetService_LongGreetServer = (*GreetService_LongGreetServer)(nil)
+
+func (s *GreetService_LongGreetServer) Recv() (*greetpb.LongGreetRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *GreetService_LongGreetServer) SendAndClose(m *greetpb.LongGreetResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the response sent with SendAndClose, if any.
+func (s *GreetService_LongGreetServer) Sent() []*greetpb.LongGreetResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*greetpb.LongGreetResponse(nil), s.sent...)
+}
+
+// GreetService_GreetEveryoneClient is a mock greetpb.GreetService_GreetEveryoneClient.
+type GreetService_GreetEveryoneClient struct {
+	ClientStream
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*greetpb.GreetEveryoneResponse
+	Err       error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*greetpb.GreetEveryoneRequest
+	received int
+}
+
+var _ greetpb.GreetService_GreetEveryoneClient = (*GreetService_GreetEveryoneClient)(nil)
+
+func (s *GreetService_GreetEveryoneClient) Send(m *greetpb.GreetEveryoneRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *GreetService_GreetEveryoneClient) Sent() []*greetpb.GreetEveryoneRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*greetpb.GreetEveryoneRequest(nil), s.sent...)
+}
+
+func (s *GreetService_GreetEveryoneClient) Recv() (*greetpb.GreetEveryoneResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+// GreetService_GreetEveryoneServer is a mock greetpb.GreetService_GreetEveryoneServer.
+// Handlers can be called with it directly.
+type GreetService_GreetEveryoneServer struct {
+	ServerStream
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*greetpb.GreetEveryoneRequest
+	Err      error
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent     []*greetpb.GreetEveryoneResponse
+	received int
+}
+
+var _ greetpb.GreetService_GreetEveryoneServer = (*GreetService_GreetEveryoneServer)(nil)
+
+func (s *GreetService_GreetEveryoneServer) Recv() (*greetpb.GreetEveryoneRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+
+func (s *GreetService_GreetEveryoneServer) Send(m *greetpb.GreetEveryoneResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *GreetService_GreetEveryoneServer) Sent() []*greetpb.GreetEveryoneResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*greetpb.GreetEveryoneResponse(nil), s.sent...)
+}
@@ -0,0 +1,146 @@
+package greetserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/greet/greetserver"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handlerResults reports the result of every streaming handler once it
+// returns.
+func handlerResults() (servertest.Option, <-chan error) {
+	done := make(chan error, 16)
+	opt := servertest.WithServerOptions(grpc.ChainStreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, ss)
+			done <- err
+			return err
+		},
+	))
+	return opt, done
+}
+
+// handlerResult waits for a streaming handler to return.
+func handlerResult(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return after the client went away")
+		return nil
+	}
+}
+
+// stillServing checks that the server answers a fresh call.
+func stillServing(t *testing.T, c greetpb.GreetServiceClient) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}})
+	if err != nil {
+		t.Fatalf("Greet after a client disconnect: %v", err)
+	}
+	if res.GetResult() != "Hello Ada" {
+		t.Errorf("Greet after a client disconnect: got %q, want %q", res.GetResult(), "Hello Ada")
+	}
+}
+
+func TestLongGreetClientDisconnect(t *testing.T) {
+	opt, done := handlerResults()
+	c := servertest.Greet(t, opt)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.LongGreet(ctx)
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	if err := stream.Send(&greetpb.LongGreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}}); err != nil {
+		t.Fatalf("LongGreet Send: %v", err)
+	}
+	cancel()
+
+	if err := handlerResult(t, done); status.Code(err) != codes.Canceled {
+		t.Errorf("LongGreet handler returned %v, want Canceled", err)
+	}
+	stillServing(t, c)
+}
+
+func TestGreetEveryoneClientDisconnect(t *testing.T) {
+	for _, room := range []string{"", "lobby"} {
+		opt, done := handlerResults()
+		c := servertest.Greet(t, opt)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := c.GreetEveryone(ctx)
+		if err != nil {
+			t.Fatalf("GreetEveryone: %v", err)
+		}
+		if err := stream.Send(&greetpb.GreetEveryoneRequest{Room: room, Greeting: &greetpb.Greeting{FirstName: "Ada"}}); err != nil {
+			t.Fatalf("GreetEveryone Send: %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("GreetEveryone Recv: %v", err)
+		}
+		cancel()
+
+		if err := handlerResult(t, done); status.Code(err) != codes.Canceled {
+			t.Errorf("GreetEveryone in room %q: handler returned %v, want Canceled", room, err)
+		}
+		stillServing(t, c)
+	}
+}
+
+// panicking fails Greet and LongGreet the way a handler bug would.
+type panicking struct {
+	*greetserver.Server
+}
+
+func (panicking) Greet(context.Context, *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	panic("greeting went wrong")
+}
+
+func (panicking) LongGreet(greetpb.GreetService_LongGreetServer) error {
+	panic("greeting went wrong")
+}
+
+func TestHandlerPanic(t *testing.T) {
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, panicking{greetserver.New()})
+	})
+	c := greetpb.NewGreetServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("Greet with a panicking handler: got %v, want Internal", err)
+	}
+
+	stream, err := c.LongGreet(ctx)
+	if err != nil {
+		t.Fatalf("LongGreet: %v", err)
+	}
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.Internal {
+		t.Fatalf("LongGreet with a panicking handler: got %v, want Internal", err)
+	}
+
+	// The server survives: handlers that do not panic still work.
+	everyone, err := c.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := everyone.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}}); err != nil {
+		t.Fatalf("GreetEveryone Send: %v", err)
+	}
+	if res, err := everyone.Recv(); err != nil || res.GetResult() != "Hello Ada!! " {
+		t.Fatalf("GreetEveryone after a panic: got (%q, %v), want %q", res.GetResult(), err, "Hello Ada!! ")
+	}
+}
@@ -0,0 +1,35 @@
+package greetserver
+
+import (
+	"context"
+
+	"github.com/pandadragoon/grpc-go-course/greet/catalog"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"google.golang.org/grpc/metadata"
+)
+
+// language picks the catalog language for g: its locale first, then the
+// accept-language metadata of the call.
+func language(ctx context.Context, g *greetpb.Greeting) string {
+	preferred := []string{g.GetLocale()}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, header := range md.Get("accept-language") {
+			preferred = append(preferred, catalog.ParseAcceptLanguage(header)...)
+		}
+	}
+	return catalog.Match(preferred...)
+}
+
+// greeting greets g in lang.
+func greeting(lang string, g *greetpb.Greeting) string {
+	style := catalog.Informal
+	if g.GetStyle() == greetpb.Style_STYLE_FORMAL {
+		style = catalog.Formal
+	}
+	return catalog.Greeting(lang, style, g.GetFirstName(), g.GetLastName())
+}
+
+// contentLanguage is the response header naming the language used.
+func contentLanguage(lang string) metadata.MD {
+	return metadata.Pairs("content-language", lang)
+}
@@ -0,0 +1,137 @@
+package greetserver
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/greet/room"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// roomBuffer is how many events a member may fall behind by before it
+	// starts missing them.
+	roomBuffer     = 64
+	maxRoomMembers = 100
+	maxRoomName    = 64
+)
+
+var roomEvents = map[room.Kind]greetpb.RoomEvent{
+	room.Message: greetpb.RoomEvent_ROOM_EVENT_GREETING,
+	room.Joined:  greetpb.RoomEvent_ROOM_EVENT_JOINED,
+	room.Left:    greetpb.RoomEvent_ROOM_EVENT_LEFT,
+}
+
+// roomName returns the room a GreetEveryone call joins, from its first
+// message or its metadata, or "" for none.
+func roomName(stream greetpb.GreetService_GreetEveryoneServer, first *greetpb.GreetEveryoneRequest) (string, error) {
+	name := first.GetRoom()
+	if name == "" {
+		if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+			if rooms := md.Get("x-room"); len(rooms) > 0 {
+				name = rooms[0]
+			}
+		}
+	}
+	if len(name) > maxRoomName {
+		return "", domain.InvalidField("", "room", fmt.Sprintf("room name is longer than %d bytes", maxRoomName))
+	}
+	return name, nil
+}
+
+// memberName names a member after the greeting they joined with.
+func memberName(g *greetpb.Greeting) string {
+	name := strings.TrimSpace(g.GetFirstName() + " " + g.GetLastName())
+	if name == "" {
+		return "anonymous"
+	}
+	return name
+}
+
+// greetRoom runs GreetEveryone in a room: first joins, its greeting and
+// every later one go to all members, and the events of the room are sent
+// back until the client closes its side.
+func (s *Server) greetRoom(stream greetpb.GreetService_GreetEveryoneServer, name string, first *greetpb.GreetEveryoneRequest) error {
+	ctx := stream.Context()
+	member, err := s.rooms.Join(name, memberName(first.GetGreeting()))
+	if err == room.ErrFull {
+		return domain.Error(codes.ResourceExhausted, ReasonRoomFull,
+			fmt.Sprintf("room %q has %d members already", name, maxRoomMembers),
+			&errdetails.ResourceInfo{ResourceType: "greet.Room", ResourceName: name},
+		)
+	}
+	if err != nil {
+		return err
+	}
+	defer member.Leave()
+
+	say := func(g *greetpb.Greeting) {
+		if g != nil {
+			member.Say(greeting(language(ctx, g), g))
+		}
+	}
+	say(first.GetGreeting())
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if req.GetRoom() != "" && req.GetRoom() != name {
+				recvErr <- domain.InvalidField(ReasonRoomChanged, "room", "the room can only be chosen in the first message")
+				return
+			}
+			say(req.GetGreeting())
+		}
+	}()
+
+	send := func(e room.Event) error {
+		res := &greetpb.GreetEveryoneResponse{
+			Event:   roomEvents[e.Kind],
+			Room:    e.Room,
+			Member:  e.Member,
+			Dropped: member.TakeDropped(),
+		}
+		switch e.Kind {
+		case room.Message:
+			res.Result = e.Text
+		case room.Joined:
+			res.Result = e.Member + " joined " + e.Room
+		case room.Left:
+			res.Result = e.Member + " left " + e.Room
+		}
+		return stream.Send(res)
+	}
+
+	for {
+		select {
+		case e := <-member.Events():
+			if err := send(e); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// Deliver what the room said up to now, including the echo of
+			// the client's last greetings.
+			member.Leave()
+			for e := range member.Events() {
+				if err := send(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package greetserver implements the greet service. Registering it is up to
+// the caller, along with the interceptors and options of the server.
+package greetserver
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/catalog"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/greet/room"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+)
+
+// domain is the ErrorInfo domain of greet errors.
+const domain rpcerr.Domain = "greet.GreetService"
+
+// Reasons of greet errors, besides rpcerr.ReasonInvalidField.
+const (
+	ReasonRoomFull    = "ROOM_FULL"
+	ReasonRoomChanged = "ROOM_CHANGED"
+)
+
+// Server implements greetpb.GreetServiceServer.
+type Server struct {
+	// Methods added to the proto answer Unimplemented until they are
+	// implemented here.
+	greetpb.UnimplementedGreetServiceServer
+
+	rooms *room.Hub
+}
+
+// New returns a greet service with no rooms yet.
+func New() *Server {
+	return &Server{rooms: room.NewHub(roomBuffer, maxRoomMembers)}
+}
+
+func (*Server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	requestid.Logf(ctx, "Greet function was invoked with %v", req)
+	lang := language(ctx, req.GetGreeting())
+	grpc.SetHeader(ctx, contentLanguage(lang))
+	result := greeting(lang, req.GetGreeting())
+	res := &greetpb.GreetResponse{
+		Result: result,
+	}
+
+	return res, nil
+}
+
+const (
+	defaultGreetCount    = 10
+	maxGreetCount        = 1000
+	defaultGreetInterval = 500 * time.Millisecond
+	maxGreetInterval     = time.Minute
+)
+
+func (*Server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+	ctx := stream.Context()
+	requestid.Logf(ctx, "GreetManyTimes function was invoked with %v", req)
+
+	count := int(req.GetCount())
+	if count == 0 {
+		count = defaultGreetCount
+	}
+	if count < 0 || count > maxGreetCount {
+		return domain.InvalidField("", "count", fmt.Sprintf("count must be between 1 and %d, got %d", maxGreetCount, count))
+	}
+	interval := defaultGreetInterval
+	if req.GetInterval() != nil {
+		if err := req.GetInterval().CheckValid(); err != nil {
+			return domain.InvalidField("", "interval", fmt.Sprintf("invalid interval: %v", err))
+		}
+		interval = req.GetInterval().AsDuration()
+	}
+	if interval < 0 || interval > maxGreetInterval {
+		return domain.InvalidField("", "interval", fmt.Sprintf("interval must be between 0 and %v, got %v", maxGreetInterval, interval))
+	}
+
+	lang := language(ctx, req.GetGreeting())
+	stream.SetHeader(contentLanguage(lang))
+	hello := greeting(lang, req.GetGreeting())
+
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			if err := sleep(ctx, interval); err != nil {
+				requestid.Logf(ctx, "Client cancelled GreetManyTimes")
+				return err
+			}
+		}
+
+		res := &greetpb.GreetManyTimesResponse{
+			Result: catalog.Times(lang, hello, i),
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for d, or returns the status for ctx if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (*Server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
+	requestid.Logf(stream.Context(), "LongGreet function was invoked with a streaming request")
+	result := ""
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&greetpb.LongGreetResponse{
+				Result: result,
+			})
+		}
+		if err != nil {
+			requestid.Logf(stream.Context(), "LongGreet: error while reading client stream: %v", err)
+			return err
+		}
+
+		g := req.GetGreeting()
+		result += greeting(language(stream.Context(), g), g) + "! "
+	}
+}
+
+func (s *Server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	name, err := roomName(stream, req)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		return s.greetRoom(stream, name, req)
+	}
+
+	for {
+		g := req.GetGreeting()
+		result := greeting(language(stream.Context(), g), g) + "!! "
+
+
+		err = stream.Send(&greetpb.GreetEveryoneResponse{Result: result})
+		if err != nil {
+			requestid.Logf(stream.Context(), "GreetEveryone: error while sending to client stream: %v", err)
+			return err
+		}
+
+		req, err = stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			requestid.Logf(stream.Context(), "GreetEveryone: error while reading client stream: %v", err)
+			return err
+		}
+	}
+}
+
+func (*Server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
+	requestid.Logf(ctx, "GreetWithDeadline function was invoked with %v", req)
+	// Simulate slow work that gives up as soon as the caller does.
+	if err := sleep(ctx, 3*time.Second); err != nil {
+		requestid.Logf(ctx, "GreetWithDeadline gave up: %v", err)
+		return nil, err
+	}
+	lang := language(ctx, req.GetGreeting())
+	grpc.SetHeader(ctx, contentLanguage(lang))
+	result := greeting(lang, req.GetGreeting())
+	res := &greetpb.GreetWithDeadlineResponse{
+		Result: result,
+	}
+
+	return res, nil
+}
@@ -0,0 +1,346 @@
+package greetserver_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestGreet(t *testing.T) {
+	c := servertest.Greet(t)
+	tests := []struct {
+		name           string
+		greeting       *greetpb.Greeting
+		acceptLanguage string
+		want           string
+		wantLanguage   string
+	}{
+		{"informal", &greetpb.Greeting{FirstName: "Ada", LastName: "Lovelace"}, "", "Hello Ada", "en"},
+		{"formal", &greetpb.Greeting{FirstName: "Ada", LastName: "Lovelace", Style: greetpb.Style_STYLE_FORMAL}, "", "Good day, Ada Lovelace", "en"},
+		{"formal without last name", &greetpb.Greeting{FirstName: "Ada", Style: greetpb.Style_STYLE_FORMAL}, "", "Hello Ada", "en"},
+		{"last name only", &greetpb.Greeting{LastName: "Lovelace"}, "", "Hello Lovelace", "en"},
+		{"locale", &greetpb.Greeting{FirstName: "Ada", Locale: "de"}, "", "Hallo Ada", "de"},
+		{"regional locale", &greetpb.Greeting{FirstName: "Ada", Locale: "de-CH"}, "", "Grüezi Ada", "de-ch"},
+		{"parent of unknown region", &greetpb.Greeting{FirstName: "Ada", Locale: "fr-CA"}, "", "Salut Ada", "fr"},
+		{"unknown locale", &greetpb.Greeting{FirstName: "Ada", Locale: "xx"}, "", "Hello Ada", "en"},
+		{"accept-language", &greetpb.Greeting{FirstName: "Ada"}, "xx, es;q=0.5, it;q=0.9", "Ciao Ada", "it"},
+		{"locale before accept-language", &greetpb.Greeting{FirstName: "Ada", Locale: "pt"}, "it", "Olá Ada", "pt"},
+		{"formal japanese", &greetpb.Greeting{FirstName: "Ada", LastName: "Lovelace", Locale: "ja", Style: greetpb.Style_STYLE_FORMAL}, "", "Lovelace様、こんにちは", "ja"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext(t)
+			if tt.acceptLanguage != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", tt.acceptLanguage)
+			}
+			var header metadata.MD
+			res, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: tt.greeting}, grpc.Header(&header))
+			if err != nil {
+				t.Fatalf("Greet: %v", err)
+			}
+			if res.GetResult() != tt.want {
+				t.Errorf("Greet: got %q, want %q", res.GetResult(), tt.want)
+			}
+			if got := header.Get("content-language"); len(got) != 1 || got[0] != tt.wantLanguage {
+				t.Errorf("content-language: got %q, want %q", got, tt.wantLanguage)
+			}
+		})
+	}
+}
+
+func TestGreetManyTimes(t *testing.T) {
+	c := servertest.Greet(t)
+	ada := &greetpb.Greeting{FirstName: "Ada"}
+	tests := []struct {
+		name     string
+		req      *greetpb.GreetManyTimesRequest
+		want     []string
+		wantCode codes.Code
+	}{
+		{
+			name: "count",
+			req:  &greetpb.GreetManyTimesRequest{Greeting: ada, Count: 3, Interval: durationpb.New(0)},
+			want: []string{"Hello Ada 0 times", "Hello Ada 1 times", "Hello Ada 2 times"},
+		},
+		{
+			name: "localized",
+			req:  &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: "Ada", Locale: "es"}, Count: 2, Interval: durationpb.New(time.Millisecond)},
+			want: []string{"Hola Ada 0 veces", "Hola Ada 1 veces"},
+		},
+		{
+			name:     "negative count",
+			req:      &greetpb.GreetManyTimesRequest{Greeting: ada, Count: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "count too large",
+			req:      &greetpb.GreetManyTimesRequest{Greeting: ada, Count: 1001},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative interval",
+			req:      &greetpb.GreetManyTimesRequest{Greeting: ada, Count: 1, Interval: durationpb.New(-time.Second)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "interval too long",
+			req:      &greetpb.GreetManyTimesRequest{Greeting: ada, Count: 1, Interval: durationpb.New(2 * time.Minute)},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetManyTimes(testContext(t), tt.req)
+			if err != nil {
+				t.Fatalf("GreetManyTimes: %v", err)
+			}
+			var got []string
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.wantCode {
+						t.Fatalf("GreetManyTimes: got %v, want %v", err, tt.wantCode)
+					}
+					return
+				}
+				got = append(got, res.GetResult())
+			}
+			if tt.wantCode != codes.OK {
+				t.Fatalf("GreetManyTimes: succeeded, want %v", tt.wantCode)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("GreetManyTimes: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetManyTimesCancel(t *testing.T) {
+	c := servertest.Greet(t)
+	ctx, cancel := context.WithCancel(testContext(t))
+	stream, err := c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{
+		Greeting: &greetpb.Greeting{FirstName: "Ada"},
+		Count:    1000,
+		Interval: durationpb.New(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("GreetManyTimes Recv: %v", err)
+	}
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("GreetManyTimes after cancel: got %v, want Canceled", err)
+	}
+}
+
+func TestLongGreet(t *testing.T) {
+	c := servertest.Greet(t)
+	tests := []struct {
+		name      string
+		greetings []*greetpb.Greeting
+		want      string
+	}{
+		{"none", nil, ""},
+		{"one", []*greetpb.Greeting{{FirstName: "Ada"}}, "Hello Ada! "},
+		{
+			"several languages",
+			[]*greetpb.Greeting{{FirstName: "Ada"}, {FirstName: "Bob", Locale: "de"}, {FirstName: "Eve", LastName: "Smith", Style: greetpb.Style_STYLE_FORMAL}},
+			"Hello Ada! Hallo Bob! Good day, Eve Smith! ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.LongGreet(testContext(t))
+			if err != nil {
+				t.Fatalf("LongGreet: %v", err)
+			}
+			for _, g := range tt.greetings {
+				if err := stream.Send(&greetpb.LongGreetRequest{Greeting: g}); err != nil {
+					t.Fatalf("LongGreet Send: %v", err)
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				t.Fatalf("LongGreet: %v", err)
+			}
+			if res.GetResult() != tt.want {
+				t.Errorf("LongGreet: got %q, want %q", res.GetResult(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetEveryone(t *testing.T) {
+	c := servertest.Greet(t)
+	tests := []struct {
+		name      string
+		greetings []*greetpb.Greeting
+		want      []string
+	}{
+		{"none", nil, nil},
+		{"one", []*greetpb.Greeting{{FirstName: "Ada"}}, []string{"Hello Ada!! "}},
+		{
+			"several",
+			[]*greetpb.Greeting{{FirstName: "Ada"}, {FirstName: "Bob", Locale: "fr"}},
+			[]string{"Hello Ada!! ", "Salut Bob!! "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetEveryone(testContext(t))
+			if err != nil {
+				t.Fatalf("GreetEveryone: %v", err)
+			}
+			var got []string
+			for _, g := range tt.greetings {
+				if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: g}); err != nil {
+					t.Fatalf("GreetEveryone Send: %v", err)
+				}
+				res, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("GreetEveryone Recv: %v", err)
+				}
+				got = append(got, res.GetResult())
+			}
+			if err := stream.CloseSend(); err != nil {
+				t.Fatalf("GreetEveryone CloseSend: %v", err)
+			}
+			if _, err := stream.Recv(); err != io.EOF {
+				t.Fatalf("GreetEveryone after CloseSend: got %v, want EOF", err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("GreetEveryone: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetEveryoneRoom(t *testing.T) {
+	c := servertest.Greet(t)
+	ctx := testContext(t)
+
+	recv := func(stream greetpb.GreetService_GreetEveryoneClient, event greetpb.RoomEvent, member, result string) {
+		t.Helper()
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("GreetEveryone Recv: %v", err)
+		}
+		if res.GetEvent() != event || res.GetRoom() != "lobby" || res.GetMember() != member || res.GetResult() != result {
+			t.Fatalf("GreetEveryone Recv: got %v, want %v from %q in lobby: %q", res, event, member, result)
+		}
+	}
+
+	ada, err := c.GreetEveryone(ctx)
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := ada.Send(&greetpb.GreetEveryoneRequest{Room: "lobby", Greeting: &greetpb.Greeting{FirstName: "Ada"}}); err != nil {
+		t.Fatalf("GreetEveryone Send: %v", err)
+	}
+	recv(ada, greetpb.RoomEvent_ROOM_EVENT_JOINED, "Ada", "Ada joined lobby")
+	recv(ada, greetpb.RoomEvent_ROOM_EVENT_GREETING, "Ada", "Hello Ada")
+
+	// The room can also be chosen through metadata.
+	bob, err := c.GreetEveryone(metadata.AppendToOutgoingContext(ctx, "x-room", "lobby"))
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+	if err := bob.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "Bob", Locale: "it"}}); err != nil {
+		t.Fatalf("GreetEveryone Send: %v", err)
+	}
+	for _, stream := range []greetpb.GreetService_GreetEveryoneClient{ada, bob} {
+		recv(stream, greetpb.RoomEvent_ROOM_EVENT_JOINED, "Bob", "Bob joined lobby")
+		recv(stream, greetpb.RoomEvent_ROOM_EVENT_GREETING, "Bob", "Ciao Bob")
+	}
+
+	if err := bob.CloseSend(); err != nil {
+		t.Fatalf("GreetEveryone CloseSend: %v", err)
+	}
+	if _, err := bob.Recv(); err != io.EOF {
+		t.Fatalf("GreetEveryone after CloseSend: got %v, want EOF", err)
+	}
+	recv(ada, greetpb.RoomEvent_ROOM_EVENT_LEFT, "Bob", "Bob left lobby")
+}
+
+func TestGreetEveryoneRoomErrors(t *testing.T) {
+	c := servertest.Greet(t)
+	tests := []struct {
+		name     string
+		requests []*greetpb.GreetEveryoneRequest
+	}{
+		{
+			"room name too long",
+			[]*greetpb.GreetEveryoneRequest{{Room: strings.Repeat("r", 65)}},
+		},
+		{
+			"room changed",
+			[]*greetpb.GreetEveryoneRequest{{Room: "lobby"}, {Room: "kitchen"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := c.GreetEveryone(testContext(t))
+			if err != nil {
+				t.Fatalf("GreetEveryone: %v", err)
+			}
+			for _, req := range tt.requests {
+				if err := stream.Send(req); err != nil {
+					t.Fatalf("GreetEveryone Send: %v", err)
+				}
+			}
+			for {
+				_, err := stream.Recv()
+				if err == nil {
+					continue
+				}
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("GreetEveryone: got %v, want InvalidArgument", err)
+				}
+				return
+			}
+		})
+	}
+}
+
+func TestGreetWithDeadline(t *testing.T) {
+	c := servertest.Greet(t)
+	req := &greetpb.GreetWithDeadlineRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.GreetWithDeadline(ctx, req); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("GreetWithDeadline with a short deadline: got %v, want DeadlineExceeded", err)
+	}
+
+	if testing.Short() {
+		t.Skip("the successful call takes 3s")
+	}
+	res, err := c.GreetWithDeadline(testContext(t), req)
+	if err != nil {
+		t.Fatalf("GreetWithDeadline: %v", err)
+	}
+	if res.GetResult() != "Hello Ada" {
+		t.Errorf("GreetWithDeadline: got %q, want %q", res.GetResult(), "Hello Ada")
+	}
+}
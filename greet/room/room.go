@@ -0,0 +1,141 @@
+// Package room broadcasts messages between the members of named rooms. Each
+// member has a bounded buffer of undelivered events: a member that falls
+// behind misses events rather than slowing down the room, and is told how
+// many it missed.
+package room
+
+import (
+	"errors"
+	"sync"
+)
+
+// Kind tells what an Event is about.
+type Kind int
+
+// Event kinds.
+const (
+	Message Kind = iota + 1
+	Joined
+	Left
+)
+
+// Event is delivered to the members of a room.
+type Event struct {
+	Kind   Kind
+	Room   string
+	Member string
+	// Text is the message of a Message event.
+	Text string
+}
+
+// ErrFull is returned by Join when the room has no space left.
+var ErrFull = errors.New("room is full")
+
+// Hub holds the rooms. A room exists while it has members.
+type Hub struct {
+	buffer     int
+	maxMembers int
+
+	mu    sync.Mutex
+	rooms map[string]map[*Member]struct{}
+}
+
+// NewHub returns a hub whose members buffer up to buffer events, in rooms
+// of up to maxMembers members.
+func NewHub(buffer, maxMembers int) *Hub {
+	return &Hub{
+		buffer:     buffer,
+		maxMembers: maxMembers,
+		rooms:      make(map[string]map[*Member]struct{}),
+	}
+}
+
+// Member is one participant of a room.
+type Member struct {
+	hub    *Hub
+	room   string
+	name   string
+	events chan Event
+	// dropped counts the events missed since TakeDropped was last called;
+	// guarded by hub.mu.
+	dropped int64
+	left    bool
+}
+
+// Join adds a member called name to room and announces it to everyone in
+// the room, the new member included.
+func (h *Hub) Join(room, name string) (*Member, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	members := h.rooms[room]
+	if len(members) >= h.maxMembers {
+		return nil, ErrFull
+	}
+	if members == nil {
+		members = make(map[*Member]struct{})
+		h.rooms[room] = members
+	}
+	m := &Member{hub: h, room: room, name: name, events: make(chan Event, h.buffer)}
+	members[m] = struct{}{}
+	h.broadcast(room, Event{Kind: Joined, Room: room, Member: name})
+	return m, nil
+}
+
+// broadcast delivers e to the members of room without waiting. h.mu must
+// be held.
+func (h *Hub) broadcast(room string, e Event) {
+	for m := range h.rooms[room] {
+		select {
+		case m.events <- e:
+		default:
+			m.dropped++
+		}
+	}
+}
+
+// Events delivers the events of the room until the member leaves.
+func (m *Member) Events() <-chan Event {
+	return m.events
+}
+
+// Say sends text to everyone in the room, the sender included.
+func (m *Member) Say(text string) {
+	m.hub.mu.Lock()
+	defer m.hub.mu.Unlock()
+	if m.left {
+		return
+	}
+	m.hub.broadcast(m.room, Event{Kind: Message, Room: m.room, Member: m.name, Text: text})
+}
+
+// TakeDropped returns how many events the member missed since the last
+// call.
+func (m *Member) TakeDropped() int64 {
+	m.hub.mu.Lock()
+	defer m.hub.mu.Unlock()
+	n := m.dropped
+	m.dropped = 0
+	return n
+}
+
+// Leave removes the member, announces it to the rest of the room and closes
+// Events. It may be called more than once.
+func (m *Member) Leave() {
+	h := m.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if m.left {
+		return
+	}
+	m.left = true
+
+	members := h.rooms[m.room]
+	delete(members, m)
+	close(m.events)
+	if len(members) == 0 {
+		delete(h.rooms, m.room)
+		return
+	}
+	h.broadcast(m.room, Event{Kind: Left, Room: m.room, Member: m.name})
+}
@@ -0,0 +1,262 @@
+// Package bench drives an RPC with many concurrent calls and measures the
+// throughput and latency the server sustains.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/calculator/stats"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPercentiles are the latency percentiles reported unless others are
+// asked for.
+var DefaultPercentiles = []float64{50, 90, 95, 99, 99.9}
+
+// Config bounds a run. It ends when Duration has passed or Calls calls were
+// made, whichever comes first; at least one of them must be set.
+type Config struct {
+	// Concurrency is the number of calls in flight at once, at least 1.
+	Concurrency int
+	// Rate limits how many calls start per second across all workers;
+	// 0 starts them as fast as the workers can make them.
+	Rate     float64
+	Duration time.Duration
+	Calls    int
+	// Timeout is the deadline of each call; 0 for none.
+	Timeout time.Duration
+	// Percentiles of the latency to report, DefaultPercentiles when nil.
+	Percentiles []float64
+}
+
+func (c Config) validate() error {
+	if c.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1, got %d", c.Concurrency)
+	}
+	if c.Rate < 0 {
+		return fmt.Errorf("rate must not be negative, got %v", c.Rate)
+	}
+	if c.Duration <= 0 && c.Calls <= 0 {
+		return fmt.Errorf("a duration or a number of calls is needed")
+	}
+	return stats.ValidatePercentiles(c.Percentiles)
+}
+
+// CallFunc makes call number n and returns how many response messages it
+// received.
+type CallFunc func(ctx context.Context, n int) (int, error)
+
+// Report is the outcome of a run.
+type Report struct {
+	Method      string  `json:"method" yaml:"method"`
+	Concurrency int     `json:"concurrency" yaml:"concurrency"`
+	TargetRate  float64 `json:"target_rate,omitempty" yaml:"target_rate,omitempty"`
+	// Elapsed is the wall time of the run in seconds.
+	Elapsed float64 `json:"elapsed_seconds" yaml:"elapsed_seconds"`
+	Calls   int     `json:"calls" yaml:"calls"`
+	Errors  int     `json:"errors" yaml:"errors"`
+	// Codes counts the calls by status code name.
+	Codes map[string]int `json:"codes" yaml:"codes"`
+	// Messages counts the response messages of all calls, which differs
+	// from Calls for streams.
+	Messages int64 `json:"messages" yaml:"messages"`
+	// CallsPerSecond and MessagesPerSecond are the throughput.
+	CallsPerSecond    float64 `json:"calls_per_second" yaml:"calls_per_second"`
+	MessagesPerSecond float64 `json:"messages_per_second" yaml:"messages_per_second"`
+	// Latency of every call, failed ones included, in milliseconds.
+	Latency Latency `json:"latency_ms" yaml:"latency_ms"`
+}
+
+// Latency summarizes call latencies in milliseconds.
+type Latency struct {
+	Min         float64      `json:"min" yaml:"min"`
+	Mean        float64      `json:"mean" yaml:"mean"`
+	Max         float64      `json:"max" yaml:"max"`
+	Percentiles []Percentile `json:"percentiles" yaml:"percentiles"`
+}
+
+// Percentile is one latency percentile.
+type Percentile struct {
+	Percentile float64 `json:"percentile" yaml:"percentile"`
+	Value      float64 `json:"value" yaml:"value"`
+}
+
+// result is the outcome of one call.
+type result struct {
+	latency  time.Duration
+	messages int
+	err      error
+}
+
+// Run calls call until cfg says to stop or ctx is done, and reports the
+// outcome. Calls still in flight when the run ends are waited for.
+func Run(ctx context.Context, cfg Config, call CallFunc) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Percentiles == nil {
+		cfg.Percentiles = DefaultPercentiles
+	}
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	// Call numbers are handed out by a single goroutine, which also paces
+	// them to the rate.
+	numbers := make(chan int)
+	go func() {
+		defer close(numbers)
+		start := time.Now()
+		for n := 0; cfg.Calls <= 0 || n < cfg.Calls; n++ {
+			if cfg.Rate > 0 {
+				next := start.Add(time.Duration(float64(n) / cfg.Rate * float64(time.Second)))
+				if err := sleepUntil(ctx, next); err != nil {
+					return
+				}
+			}
+			select {
+			case numbers <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var results []result
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local []result
+			for n := range numbers {
+				local = append(local, measure(cfg.Timeout, call, n))
+			}
+			mu.Lock()
+			results = append(results, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return report(cfg, results, time.Since(start)), nil
+}
+
+// measure makes one call. Calls get a context of their own rather than the
+// run's, so the end of the run does not fail the calls in flight.
+func measure(timeout time.Duration, call CallFunc, n int) result {
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	start := time.Now()
+	messages, err := call(ctx, n)
+	return result{latency: time.Since(start), messages: messages, err: err}
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func report(cfg Config, results []result, elapsed time.Duration) *Report {
+	r := &Report{
+		Concurrency: cfg.Concurrency,
+		TargetRate:  cfg.Rate,
+		Elapsed:     elapsed.Seconds(),
+		Calls:       len(results),
+		Codes:       make(map[string]int),
+	}
+	latencies := make([]float64, len(results))
+	var sum float64
+	for i, res := range results {
+		r.Codes[status.Code(res.err).String()]++
+		if res.err != nil {
+			r.Errors++
+		}
+		r.Messages += int64(res.messages)
+		latencies[i] = float64(res.latency) / float64(time.Millisecond)
+		sum += latencies[i]
+	}
+	if elapsed > 0 {
+		r.CallsPerSecond = float64(r.Calls) / elapsed.Seconds()
+		r.MessagesPerSecond = float64(r.Messages) / elapsed.Seconds()
+	}
+
+	r.Latency.Percentiles = []Percentile{}
+	if len(latencies) == 0 {
+		return r
+	}
+	sort.Float64s(latencies)
+	r.Latency.Min = latencies[0]
+	r.Latency.Max = latencies[len(latencies)-1]
+	r.Latency.Mean = sum / float64(len(latencies))
+	for _, p := range cfg.Percentiles {
+		r.Latency.Percentiles = append(r.Latency.Percentiles, Percentile{
+			Percentile: p,
+			Value:      stats.PercentileOf(latencies, p),
+		})
+	}
+	return r
+}
+
+// WriteText writes r for people to read.
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Method:\t%s\n", r.Method)
+	if r.TargetRate > 0 {
+		fmt.Fprintf(tw, "Concurrency:\t%d (at most %v calls/s)\n", r.Concurrency, r.TargetRate)
+	} else {
+		fmt.Fprintf(tw, "Concurrency:\t%d\n", r.Concurrency)
+	}
+	fmt.Fprintf(tw, "Elapsed:\t%.2fs\n", r.Elapsed)
+	fmt.Fprintf(tw, "Calls:\t%d (%d failed)\n", r.Calls, r.Errors)
+	fmt.Fprintf(tw, "Throughput:\t%.1f calls/s\n", r.CallsPerSecond)
+	if r.Messages != int64(r.Calls-r.Errors) {
+		fmt.Fprintf(tw, "Messages:\t%d (%.1f/s)\n", r.Messages, r.MessagesPerSecond)
+	}
+	fmt.Fprintln(tw, "\nLatency:")
+	fmt.Fprintf(tw, "  min\t%s\n", ms(r.Latency.Min))
+	fmt.Fprintf(tw, "  mean\t%s\n", ms(r.Latency.Mean))
+	for _, p := range r.Latency.Percentiles {
+		fmt.Fprintf(tw, "  p%v\t%s\n", p.Percentile, ms(p.Value))
+	}
+	fmt.Fprintf(tw, "  max\t%s\n", ms(r.Latency.Max))
+
+	codes := make([]string, 0, len(r.Codes))
+	for code := range r.Codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	fmt.Fprintln(tw, "\nStatus codes:")
+	for _, code := range codes {
+		fmt.Fprintf(tw, "  %s\t%d\n", code, r.Codes[code])
+	}
+	return tw.Flush()
+}
+
+func ms(v float64) string {
+	return time.Duration(v * float64(time.Millisecond)).Round(time.Microsecond).String()
+}
@@ -0,0 +1,232 @@
+package bench_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/blog/blogserver"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorserver"
+	"github.com/pandadragoon/grpc-go-course/internal/bench"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+)
+
+func calculatorConn(t *testing.T) *grpc.ClientConn {
+	return servertest.Start(t, func(s *grpc.Server) {
+		calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New(nil))
+	})
+}
+
+func caller(t *testing.T, conn *grpc.ClientConn, method, data string) *bench.Caller {
+	t.Helper()
+	m, err := bench.FindMethod(method)
+	if err != nil {
+		t.Fatalf("FindMethod(%q): %v", method, err)
+	}
+	p, err := bench.NewPayload(m.Input(), data, 1)
+	if err != nil {
+		t.Fatalf("NewPayload(%q): %v", data, err)
+	}
+	return &bench.Caller{Conn: conn, Method: m, Request: p.Build, Messages: 5}
+}
+
+func TestFindMethod(t *testing.T) {
+	for _, name := range []string{
+		"Sum",
+		"calculator.CalculatorService/Sum",
+		"calculator.CalculatorService.Sum",
+		"/calculator.CalculatorService/Sum",
+	} {
+		m, err := bench.FindMethod(name)
+		if err != nil {
+			t.Errorf("FindMethod(%q): %v", name, err)
+			continue
+		}
+		if got := m.FullName(); got != "/calculator.CalculatorService/Sum" {
+			t.Errorf("FindMethod(%q) = %s", name, got)
+		}
+	}
+
+	if _, err := bench.FindMethod("Nope"); err == nil {
+		t.Error("FindMethod(Nope) succeeded")
+	}
+}
+
+func TestMethods(t *testing.T) {
+	kinds := map[string]string{}
+	for _, m := range bench.Methods() {
+		kinds[m.FullName()] = m.Kind()
+	}
+	for name, want := range map[string]string{
+		"/greet.GreetService/Greet":                    "unary",
+		"/blog.BlogService/ListBlog":                   "server streaming",
+		"/calculator.CalculatorService/ComputeAverage": "client streaming",
+		"/calculator.CalculatorService/FindMaximum":    "bidi streaming",
+	} {
+		if got := kinds[name]; got != want {
+			t.Errorf("kind of %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPayload(t *testing.T) {
+	m, _ := bench.FindMethod("Sum")
+	p, err := bench.NewPayload(m.Input(), `{"first_number": {{rand 1 3}}, "second_number": {{.Call}}}`, 1)
+	if err != nil {
+		t.Fatalf("NewPayload: %v", err)
+	}
+	for n := 0; n < 20; n++ {
+		msg, err := p.Build(n, 0)
+		if err != nil {
+			t.Fatalf("Build(%d): %v", n, err)
+		}
+		req := msg.(*calculatorpb.SumRequest)
+		if req.GetSecondNumber() != int32(n) {
+			t.Errorf("Build(%d).second_number = %d", n, req.GetSecondNumber())
+		}
+		if f := req.GetFirstNumber(); f < 1 || f > 3 {
+			t.Errorf("Build(%d).first_number = %d, want 1 to 3", n, f)
+		}
+	}
+
+	for _, data := range []string{
+		`{"first_number": "x"}`,
+		`{"no_such_field": 1}`,
+		`{"first_number": {{rand 3 1}}}`,
+		`{{`,
+	} {
+		if _, err := bench.NewPayload(m.Input(), data, 1); err == nil {
+			t.Errorf("NewPayload(%q) succeeded", data)
+		}
+	}
+}
+
+func TestRunUnary(t *testing.T) {
+	c := caller(t, calculatorConn(t), "Sum", `{"first_number": {{.Call}}, "second_number": 2}`)
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 4, Calls: 50}, c.Call)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Calls != 50 || r.Errors != 0 || r.Codes["OK"] != 50 || r.Messages != 50 {
+		t.Errorf("report = %+v, want 50 successful calls", r)
+	}
+	if len(r.Latency.Percentiles) != len(bench.DefaultPercentiles) {
+		t.Errorf("got %d percentiles, want %d", len(r.Latency.Percentiles), len(bench.DefaultPercentiles))
+	}
+	if r.Latency.Min > r.Latency.Mean || r.Latency.Mean > r.Latency.Max {
+		t.Errorf("latency = %+v, want min <= mean <= max", r.Latency)
+	}
+
+	var text strings.Builder
+	if err := r.WriteText(&text); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	for _, want := range []string{"Calls:", "p99.9", "OK"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text report lacks %q:\n%s", want, text.String())
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	c := caller(t, calculatorConn(t), "SquareRoot", `{"number": {{pick 4 -1}}}`)
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 2, Calls: 40}, c.Call)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Errors == 0 || r.Errors != r.Codes["InvalidArgument"] || r.Codes["OK"]+r.Errors != 40 {
+		t.Errorf("codes = %v, errors = %d; want OK and InvalidArgument", r.Codes, r.Errors)
+	}
+}
+
+func TestRunStreams(t *testing.T) {
+	conn := calculatorConn(t)
+
+	c := caller(t, conn, "ComputeAverage", `{"number": {{.Message}}}`)
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 2, Calls: 10}, c.Call)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Errors != 0 || r.Messages != 10 {
+		t.Errorf("ComputeAverage report = %+v, want 10 responses", r)
+	}
+
+	// Each message is a new maximum.
+	c = caller(t, conn, "FindMaximum", `{"number": {{.Message}}}`)
+	r, err = bench.Run(context.Background(), bench.Config{Concurrency: 2, Calls: 10}, c.Call)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Errors != 0 || r.Messages != 50 {
+		t.Errorf("FindMaximum report = %+v, want 50 responses", r)
+	}
+}
+
+func TestRunListBlog(t *testing.T) {
+	store := blogserver.NewMemoryStore()
+	for i := 0; i < 3; i++ {
+		if _, err := store.Create(context.Background(), &blogpb.Blog{AuthorId: "ada", Title: "Notes"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		blogpb.RegisterBlogServiceServer(s, blogserver.New(store))
+	})
+
+	c := caller(t, conn, "ListBlog", "{}")
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 3, Calls: 12}, c.Call)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Errors != 0 || r.Messages != 36 {
+		t.Errorf("report = %+v, want 36 responses", r)
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	start := time.Now()
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 4, Rate: 100, Calls: 11}, func(ctx context.Context, n int) (int, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// The 11th call starts 100ms after the first.
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("11 calls at 100/s took %v", elapsed)
+	}
+	if r.Calls != 11 {
+		t.Errorf("made %d calls, want 11", r.Calls)
+	}
+}
+
+func TestRunDuration(t *testing.T) {
+	r, err := bench.Run(context.Background(), bench.Config{Concurrency: 2, Duration: 50 * time.Millisecond}, func(ctx context.Context, n int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if r.Calls == 0 || r.Elapsed < 0.05 {
+		t.Errorf("report = %+v, want calls over 50ms", r)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	nop := func(context.Context, int) (int, error) { return 0, nil }
+	for _, cfg := range []bench.Config{
+		{Concurrency: 0, Calls: 1},
+		{Concurrency: 1},
+		{Concurrency: 1, Calls: 1, Rate: -1},
+		{Concurrency: 1, Calls: 1, Percentiles: []float64{101}},
+	} {
+		if _, err := bench.Run(context.Background(), cfg, nop); err == nil {
+			t.Errorf("Run(%+v) succeeded", cfg)
+		}
+	}
+}
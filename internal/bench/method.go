@@ -0,0 +1,234 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// The services that can be benchmarked.
+	_ "github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	_ "github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	_ "github.com/pandadragoon/grpc-go-course/greet/greetpb"
+)
+
+// services are the services Methods and FindMethod know about.
+var services = []protoreflect.FullName{
+	"greet.GreetService",
+	"calculator.CalculatorService",
+	"blog.BlogService",
+}
+
+// Method is an RPC that can be benchmarked.
+type Method struct {
+	desc protoreflect.MethodDescriptor
+}
+
+// Service returns the fully qualified service name, such as
+// "calculator.CalculatorService".
+func (m Method) Service() string {
+	return string(m.desc.Parent().FullName())
+}
+
+// FullName returns the gRPC method name, such as
+// "/calculator.CalculatorService/Sum".
+func (m Method) FullName() string {
+	return "/" + m.Service() + "/" + string(m.desc.Name())
+}
+
+// Kind describes how the method streams: "unary", "server streaming",
+// "client streaming" or "bidi streaming".
+func (m Method) Kind() string {
+	switch {
+	case m.desc.IsStreamingClient() && m.desc.IsStreamingServer():
+		return "bidi streaming"
+	case m.desc.IsStreamingClient():
+		return "client streaming"
+	case m.desc.IsStreamingServer():
+		return "server streaming"
+	}
+	return "unary"
+}
+
+// Input returns the request message descriptor.
+func (m Method) Input() protoreflect.MessageDescriptor {
+	return m.desc.Input()
+}
+
+// Methods returns every method of the three services.
+func Methods() []Method {
+	var methods []Method
+	for _, name := range services {
+		svc := findService(name)
+		for i := 0; i < svc.Methods().Len(); i++ {
+			methods = append(methods, Method{desc: svc.Methods().Get(i)})
+		}
+	}
+	return methods
+}
+
+func findService(name protoreflect.FullName) protoreflect.ServiceDescriptor {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		panic(fmt.Sprintf("bench: service %s is not registered: %v", name, err))
+	}
+	return d.(protoreflect.ServiceDescriptor)
+}
+
+// FindMethod looks a method up by its gRPC name ("/blog.BlogService/ListBlog"),
+// its qualified name ("blog.BlogService/ListBlog" or "blog.BlogService.ListBlog")
+// or just its name ("ListBlog") when that is unique across the services.
+func FindMethod(name string) (Method, error) {
+	name = strings.TrimPrefix(name, "/")
+	var matches []Method
+	for _, m := range Methods() {
+		full := m.FullName()[1:]
+		if name == full || name == string(m.desc.FullName()) || name == string(m.desc.Name()) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Method{}, fmt.Errorf("unknown method %q", name)
+	case 1:
+		return matches[0], nil
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, m.FullName())
+	}
+	sort.Strings(names)
+	return Method{}, fmt.Errorf("method %q is ambiguous: %s", name, strings.Join(names, ", "))
+}
+
+// Caller is what a benchmarked call sends.
+type Caller struct {
+	Conn   *grpc.ClientConn
+	Method Method
+	// Request builds the request for call n. Unary and server streaming
+	// calls send it once; client and bidi streaming calls send Messages of
+	// them, numbered from 0 in their second argument.
+	Request func(n, i int) (proto.Message, error)
+	// First, when set, builds the message a client or bidi stream starts
+	// with, for methods whose first message holds options.
+	First func(n int) (proto.Message, error)
+	// Messages is how many requests a client or bidi stream sends.
+	Messages int
+}
+
+// Call makes call number n and returns how many responses it received. It
+// is a CallFunc.
+func (c *Caller) Call(ctx context.Context, n int) (int, error) {
+	desc := c.Method.desc
+	if !desc.IsStreamingClient() && !desc.IsStreamingServer() {
+		req, err := c.Request(n, 0)
+		if err != nil {
+			return 0, err
+		}
+		res := c.newResponse()
+		if err := c.Conn.Invoke(ctx, c.Method.FullName(), req, res); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.Conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(desc.Name()),
+		ServerStreams: desc.IsStreamingServer(),
+		ClientStreams: desc.IsStreamingClient(),
+	}, c.Method.FullName())
+	if err != nil {
+		return 0, err
+	}
+
+	// Bidi responses are read while requests are still being sent, so a
+	// server that answers as it goes is not held up by a full window.
+	received := make(chan recvResult, 1)
+	if desc.IsStreamingServer() {
+		go func() { received <- c.recvAll(stream) }()
+	}
+
+	if err := c.send(stream, n); err != nil {
+		return 0, err
+	}
+
+	if !desc.IsStreamingServer() {
+		if err := stream.RecvMsg(c.newResponse()); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	r := <-received
+	return r.n, r.err
+}
+
+type recvResult struct {
+	n   int
+	err error
+}
+
+func (c *Caller) recvAll(stream grpc.ClientStream) recvResult {
+	var n int
+	for {
+		err := stream.RecvMsg(c.newResponse())
+		if err == io.EOF {
+			return recvResult{n: n}
+		}
+		if err != nil {
+			return recvResult{n: n, err: err}
+		}
+		n++
+	}
+}
+
+// send writes the requests of call n and closes the sending side. Errors
+// from SendMsg itself are left to the receiving side, which gets the status
+// of the call.
+func (c *Caller) send(stream grpc.ClientStream, n int) error {
+	var msgs []func() (proto.Message, error)
+	if c.Method.desc.IsStreamingClient() {
+		if c.First != nil {
+			msgs = append(msgs, func() (proto.Message, error) { return c.First(n) })
+		}
+		for i := 0; i < c.Messages; i++ {
+			i := i
+			msgs = append(msgs, func() (proto.Message, error) { return c.Request(n, i) })
+		}
+	} else {
+		msgs = append(msgs, func() (proto.Message, error) { return c.Request(n, 0) })
+	}
+
+	for _, build := range msgs {
+		req, err := build()
+		if err != nil {
+			return err
+		}
+		if err := stream.SendMsg(req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+	}
+	return stream.CloseSend()
+}
+
+func (c *Caller) newResponse() proto.Message {
+	return newMessage(c.Method.desc.Output())
+}
+
+func newMessage(md protoreflect.MessageDescriptor) proto.Message {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil {
+		panic(fmt.Sprintf("bench: message %s is not registered: %v", md.FullName(), err))
+	}
+	return mt.New().Interface()
+}
@@ -0,0 +1,124 @@
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"text/template"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Payload builds request messages from a template of their JSON form.
+//
+// The template is text/template with the call number as .Call and the
+// number of the message within a client stream as .Message, and these
+// functions:
+//
+//	rand a b    a random integer in [a, b]
+//	randf a b   a random float in [a, b)
+//	str n       a random string of n letters
+//	pick a b... one of its arguments at random
+//
+// For example {"first_number": {{rand 1 100}}, "second_number": {{.Call}}}
+// for Sum, or {"blog": {"title": "{{str 20}}"}} for CreateBlog.
+type Payload struct {
+	md protoreflect.MessageDescriptor
+	// static is the message when the template has no actions, built once.
+	static proto.Message
+	tmpl   *template.Template
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewPayload parses text as a template for md messages. The template is
+// checked by building the first message.
+func NewPayload(md protoreflect.MessageDescriptor, text string, seed int64) (*Payload, error) {
+	p := &Payload{md: md, rnd: rand.New(rand.NewSource(seed))}
+	if !strings.Contains(text, "{{") {
+		m, err := p.unmarshal([]byte(text))
+		if err != nil {
+			return nil, err
+		}
+		p.static = m
+		return p, nil
+	}
+
+	tmpl, err := template.New(string(md.Name())).Funcs(template.FuncMap{
+		"rand":  p.randInt,
+		"randf": p.randFloat,
+		"str":   p.randString,
+		"pick":  p.pick,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %v", md.Name(), err)
+	}
+	p.tmpl = tmpl
+	if _, err := p.Build(0, 0); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Build returns message i of call n. It has the signature of
+// Caller.Request.
+func (p *Payload) Build(n, i int) (proto.Message, error) {
+	if p.static != nil {
+		return p.static, nil
+	}
+	var buf bytes.Buffer
+	err := p.tmpl.Execute(&buf, struct{ Call, Message int }{n, i})
+	if err != nil {
+		return nil, fmt.Errorf("executing %s template: %v", p.md.Name(), err)
+	}
+	return p.unmarshal(buf.Bytes())
+}
+
+func (p *Payload) unmarshal(b []byte) (proto.Message, error) {
+	m := newMessage(p.md)
+	if err := protojson.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %v", p.md.Name(), b, err)
+	}
+	return m, nil
+}
+
+func (p *Payload) randInt(a, b int) (int, error) {
+	if b < a {
+		return 0, fmt.Errorf("rand: %d is less than %d", b, a)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return a + p.rnd.Intn(b-a+1), nil
+}
+
+func (p *Payload) randFloat(a, b float64) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return a + p.rnd.Float64()*(b-a)
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+func (p *Payload) randString(n int) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[p.rnd.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func (p *Payload) pick(choices ...interface{}) (interface{}, error) {
+	if len(choices) == 0 {
+		return nil, fmt.Errorf("pick: nothing to pick from")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return choices[p.rnd.Intn(len(choices))], nil
+}
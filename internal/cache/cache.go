@@ -0,0 +1,118 @@
+// Package cache is a size-bounded LRU cache whose entries expire after a
+// fixed time. It is safe for concurrent use. A nil *Cache is a valid cache
+// that stores nothing, so callers need no special case when caching is
+// disabled.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats counts cache lookups and removals since the cache was created.
+type Stats struct {
+	Entries   int   `json:"entries"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Expired   int64 `json:"expired"`
+}
+
+// Cache maps string keys to values.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	lru     *list.List // front is most recently used
+	stats   Stats
+	// now is time.Now, replaced by tests.
+	now func() time.Time
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// New returns a cache holding up to size entries for ttl each, or nil when
+// size is not positive. A ttl of 0 keeps entries until they are evicted.
+func New(size int, ttl time.Duration) *Cache {
+	if size <= 0 {
+		return nil
+	}
+	return &Cache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value stored under key, if any and not expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		c.remove(el)
+		c.stats.Expired++
+		c.stats.Misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Add stores value under key, evicting the least recently used entry when
+// the cache is full.
+func (c *Cache) Add(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Stats returns the current counters.
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = c.lru.Len()
+	return s
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEviction(t *testing.T) {
+	c := New(3, 0)
+	for _, k := range []string{"a", "b", "c"} {
+		c.Add(k, k)
+	}
+	// Using a and updating b leaves c the least recently used.
+	c.Get("a")
+	c.Add("b", "B")
+	c.Add("d", "d")
+	if _, ok := c.Get("c"); ok {
+		t.Errorf("c was not evicted")
+	}
+	// Misses do not change the order, so a goes next.
+	c.Add("e", "e")
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("a was not evicted after c")
+	}
+	if v, ok := c.Get("b"); !ok || v != "B" {
+		t.Errorf("Get(b) = %v, %v, want the updated B", v, ok)
+	}
+
+	s := c.Stats()
+	if s.Entries != 3 || s.Evictions != 2 {
+		t.Errorf("Stats = %+v, want 3 entries and 2 evictions", s)
+	}
+}
+
+// clock is a time that only moves when told to.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTimed returns a cache for size and ttl whose time moves with the
+// returned clock.
+func newTimed(size int, ttl time.Duration) (*Cache, *clock) {
+	c := New(size, ttl)
+	clk := &clock{now: time.Now()}
+	c.now = clk.Now
+	return c, clk
+}
+
+func TestExpiry(t *testing.T) {
+	const ttl = time.Minute
+	c, clk := newTimed(10, ttl)
+	c.Add("old", 1)
+	clk.Advance(ttl / 2)
+	c.Add("new", 2)
+	// Adding again extends an entry's life.
+	c.Add("renewed", 3)
+
+	clk.Advance(ttl / 2)
+	c.Add("renewed", 4)
+	if _, ok := c.Get("old"); ok {
+		t.Errorf("old entry did not expire after exactly ttl")
+	}
+	clk.Advance(ttl/2 - time.Nanosecond)
+	if v, ok := c.Get("new"); !ok || v != 2 {
+		t.Errorf("Get(new) = %v, %v, want 2", v, ok)
+	}
+
+	clk.Advance(time.Nanosecond)
+	if _, ok := c.Get("new"); ok {
+		t.Errorf("new entry did not expire")
+	}
+	if v, ok := c.Get("renewed"); !ok || v != 4 {
+		t.Errorf("Get(renewed) = %v, %v, want 4", v, ok)
+	}
+
+	s := c.Stats()
+	if s.Entries != 1 || s.Expired != 2 || s.Hits != 2 || s.Misses != 2 {
+		t.Errorf("Stats = %+v, want 1 entry, 2 expired, 2 hits and 2 misses", s)
+	}
+}
+
+func TestNoExpiry(t *testing.T) {
+	c, clk := newTimed(1, 0)
+	c.Add("k", "v")
+	clk.Advance(24 * time.Hour)
+	if v, ok := c.Get("k"); !ok || v != "v" {
+		t.Errorf("Get(k) = %v, %v, want v", v, ok)
+	}
+}
+
+func TestNil(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		c := New(size, time.Minute)
+		if c != nil {
+			t.Fatalf("New(%d) = %v, want nil", size, c)
+		}
+		c.Add("k", "v")
+		if v, ok := c.Get("k"); ok {
+			t.Errorf("nil cache: Get(k) = %v, want nothing", v)
+		}
+		if s := c.Stats(); s != (Stats{}) {
+			t.Errorf("nil cache: Stats = %+v, want zero", s)
+		}
+	}
+}
+
+// TestExpvar checks the JSON the calculator server publishes the stats as.
+func TestExpvar(t *testing.T) {
+	c := New(1, 0)
+	c.Add("a", 1)
+	c.Get("a")
+	c.Get("b")
+	c.Add("b", 2)
+
+	v := expvar.Func(func() interface{} { return c.Stats() })
+	var got map[string]int64
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("%s: %v", v, err)
+	}
+	want := map[string]int64{"entries": 1, "hits": 1, "misses": 1, "evictions": 1, "expired": 0}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	c := New(16, time.Minute)
+	done := make(chan bool)
+	for g := 0; g < 8; g++ {
+		go func(g int) {
+			for i := 0; i < 1000; i++ {
+				k := fmt.Sprint((g + i) % 32)
+				if _, ok := c.Get(k); !ok {
+					c.Add(k, i)
+				}
+			}
+			done <- true
+		}(g)
+	}
+	for g := 0; g < 8; g++ {
+		<-done
+	}
+	s := c.Stats()
+	if s.Entries != 16 || s.Hits+s.Misses != 8000 {
+		t.Errorf("Stats = %+v, want 16 entries and 8000 lookups", s)
+	}
+}
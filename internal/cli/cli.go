@@ -0,0 +1,133 @@
+// Package cli holds the pieces shared by the service command-line clients:
+// connection flags, output formatting and exit status mapping.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit statuses that do not come from a gRPC status code. Errors returned by
+// an RPC exit with the numeric value of their status code (NOT_FOUND is 5,
+// UNAVAILABLE is 14, ...), so scripts can branch on them directly.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 64
+)
+
+// UsageError reports a command line that could not be understood.
+type UsageError struct {
+	msg string
+	// reported is set when the flag package already printed the error.
+	reported bool
+}
+
+func (e *UsageError) Error() string { return e.msg }
+
+// Usagef builds a UsageError.
+func Usagef(format string, args ...interface{}) error {
+	return &UsageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ExitCode returns the process exit status for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) || errors.Is(err, flag.ErrHelp) {
+		return ExitUsage
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.OK {
+		return int(s.Code())
+	}
+	return ExitFailure
+}
+
+// Command is a single subcommand of a client binary.
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(args []string) error
+}
+
+// Main dispatches os.Args to the matching command, reports any error on
+// stderr and exits with the status from ExitCode.
+func Main(program string, commands []Command) {
+	os.Exit(run(os.Stderr, program, commands, os.Args[1:]))
+}
+
+func run(stderr io.Writer, program string, commands []Command, args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr, program, commands)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.Name != args[0] {
+			continue
+		}
+		err := cmd.Run(args[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		var usageErr *UsageError
+		if err != nil && !(errors.As(err, &usageErr) && usageErr.reported) {
+			fmt.Fprintf(stderr, "%s %s: %v\n", program, cmd.Name, describe(err))
+		}
+		return ExitCode(err)
+	}
+
+	fmt.Fprintf(stderr, "%s: unknown command %q\n\n", program, args[0])
+	printUsage(stderr, program, commands)
+	return ExitUsage
+}
+
+// describe presents err with the error details the server sent.
+func describe(err error) string {
+	if _, ok := status.FromError(err); ok {
+		return rpcerr.Decode(err).String()
+	}
+	return err.Error()
+}
+
+func printUsage(w io.Writer, program string, commands []Command) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [args]\n\nCommands:\n", program)
+	sorted := append([]Command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, cmd := range sorted {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.Name, cmd.Summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", program)
+}
+
+// NewFlagSet returns a flag set that reports parse errors instead of exiting,
+// so they can be mapped to ExitUsage.
+func NewFlagSet(program, command string) *flag.FlagSet {
+	fs := flag.NewFlagSet(program+" "+command, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// Parse parses args into fs, turning parse failures into UsageErrors.
+func Parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &UsageError{msg: err.Error(), reported: true}
+	}
+	return nil
+}
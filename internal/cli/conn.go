@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/client"
+)
+
+// ConnFlags are the connection settings every client command accepts.
+type ConnFlags struct {
+	Addr       string
+	TLS        bool
+	CAFile     string
+	ServerName string
+	Token      string
+	ClientID   string
+	RequestID  string
+	Compress   string
+	Timeout    time.Duration
+}
+
+// Register adds the connection flags to fs. timeout is the default deadline
+// for the command; zero leaves it to the client library's method defaults.
+func (c *ConnFlags) Register(fs *flag.FlagSet, timeout time.Duration) {
+	fs.StringVar(&c.Addr, "addr", "localhost:50051", "server address, a resolver target such as dns:///host:port, or several addresses separated by commas")
+	fs.BoolVar(&c.TLS, "tls", false, "connect using TLS")
+	fs.StringVar(&c.CAFile, "ca", "ssl/ca.crt", "CA certificate used to verify the server when -tls is set")
+	fs.StringVar(&c.ServerName, "server-name", "", "override the server name checked against the certificate")
+	fs.StringVar(&c.Token, "token", "", "bearer token sent in the authorization metadata")
+	fs.StringVar(&c.ClientID, "client-id", "", "identify as this client to the server's rate limits")
+	fs.StringVar(&c.RequestID, "request-id", "", "request ID to send, for finding the call in the server logs (default random)")
+	fs.StringVar(&c.Compress, "compress", "", "compress messages with gzip or snappy, and ask the server to do the same")
+	fs.DurationVar(&c.Timeout, "timeout", timeout, "deadline for the call (0 for the client default)")
+}
+
+// Target returns the dial target for -addr.
+func (c *ConnFlags) Target() string {
+	return client.Target(strings.Split(c.Addr, ",")...)
+}
+
+// ClientOptions returns the client library options for the flags.
+func (c *ConnFlags) ClientOptions() []client.Option {
+	var opts []client.Option
+	if c.TLS {
+		opts = append(opts, client.WithTLS(c.CAFile, c.ServerName))
+	}
+	if c.Token != "" {
+		opts = append(opts, client.WithToken(c.Token))
+	}
+	if c.ClientID != "" {
+		opts = append(opts, client.WithClientID(c.ClientID))
+	}
+	if c.Compress != "" {
+		opts = append(opts, client.WithCompression(c.Compress))
+	}
+	return opts
+}
+
+// Context returns the context for a call, bounded by -timeout when set and
+// sending -request-id when set.
+func (c *ConnFlags) Context() (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if c.RequestID != "" {
+		ctx = client.ContextWithRequestID(ctx, c.RequestID)
+	}
+	if c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v2"
+)
+
+// Format is an output format selectable with -o.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+func (f *Format) String() string { return string(*f) }
+
+// Set implements flag.Value.
+func (f *Format) Set(s string) error {
+	switch Format(s) {
+	case FormatTable, FormatJSON, FormatYAML:
+		*f = Format(s)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (want table, json or yaml)", s)
+}
+
+// Printer renders protobuf messages in the selected format. Messages are
+// buffered until Flush so table columns line up and JSON lists are valid.
+type Printer struct {
+	w      io.Writer
+	format Format
+	list   bool
+	msgs   []proto.Message
+}
+
+// NewPrinter returns a Printer writing to w. When list is set the output is
+// always a table, JSON array or YAML sequence, even for zero or one message.
+func NewPrinter(w io.Writer, format Format, list bool) *Printer {
+	return &Printer{w: w, format: format, list: list}
+}
+
+// Add queues m for output.
+func (p *Printer) Add(m proto.Message) {
+	p.msgs = append(p.msgs, m)
+}
+
+// Flush writes every queued message.
+func (p *Printer) Flush() error {
+	defer func() { p.msgs = nil }()
+	switch p.format {
+	case FormatJSON:
+		return p.flushJSON()
+	case FormatYAML:
+		return p.flushYAML()
+	default:
+		return p.flushTable()
+	}
+}
+
+// WriteJSONLine writes m to w as a single line of JSON, for output that is
+// streamed rather than buffered by a Printer.
+func WriteJSONLine(w io.Writer, m proto.Message) error {
+	b, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (p *Printer) flushJSON() error {
+	marshal := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	raw := make([]json.RawMessage, 0, len(p.msgs))
+	for _, m := range p.msgs {
+		b, err := marshal.Marshal(m)
+		if err != nil {
+			return err
+		}
+		raw = append(raw, b)
+	}
+
+	var out []byte
+	var err error
+	if !p.list && len(raw) == 1 {
+		out, err = json.Marshal(raw[0])
+	} else {
+		out, err = json.Marshal(raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, out, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(p.w)
+	return err
+}
+
+func (p *Printer) flushYAML() error {
+	var doc interface{}
+	if !p.list && len(p.msgs) == 1 {
+		doc = yamlMessage(p.msgs[0].ProtoReflect())
+	} else {
+		items := make([]interface{}, 0, len(p.msgs))
+		for _, m := range p.msgs {
+			items = append(items, yamlMessage(m.ProtoReflect()))
+		}
+		doc = items
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(out)
+	return err
+}
+
+func (p *Printer) flushTable() error {
+	if len(p.msgs) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fields := p.msgs[0].ProtoReflect().Descriptor().Fields()
+	header := make([]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		header[i] = strings.ToUpper(string(fields.Get(i).Name()))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, m := range p.msgs {
+		rm := m.ProtoReflect()
+		row := make([]string, fields.Len())
+		for i := 0; i < fields.Len(); i++ {
+			row[i] = tableCell(fields.Get(i), rm.Get(fields.Get(i)))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func tableCell(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if fd.IsList() {
+		list := v.List()
+		parts := make([]string, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			parts[i] = cellScalar(fd, list.Get(i))
+		}
+		return strings.Join(parts, ",")
+	}
+	if fd.IsMap() {
+		var parts []string
+		for _, item := range yamlValue(fd, v).(yaml.MapSlice) {
+			parts = append(parts, fmt.Sprintf("%v=%v", item.Key, item.Value))
+		}
+		return strings.Join(parts, ",")
+	}
+	// Tabs and newlines would break the column layout.
+	return strings.NewReplacer("\t", " ", "\n", " ").Replace(cellScalar(fd, v))
+}
+
+func cellScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		if !v.Message().IsValid() {
+			return ""
+		}
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return "?"
+		}
+		return string(b)
+	}
+	return fmt.Sprint(yamlScalar(fd, v))
+}
+
+// yamlMessage converts m to an ordered map so fields come out in declaration
+// order rather than sorted by name.
+func yamlMessage(m protoreflect.Message) yaml.MapSlice {
+	fields := m.Descriptor().Fields()
+	out := make(yaml.MapSlice, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		out = append(out, yaml.MapItem{Key: string(fd.Name()), Value: yamlValue(fd, m.Get(fd))})
+	}
+	return out
+}
+
+func yamlValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]interface{}, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items[i] = yamlScalar(fd, list.Get(i))
+		}
+		return items
+	case fd.IsMap():
+		var items yaml.MapSlice
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			items = append(items, yaml.MapItem{Key: k.String(), Value: yamlScalar(fd.MapValue(), mv)})
+			return true
+		})
+		sort.Slice(items, func(i, j int) bool { return items[i].Key.(string) < items[j].Key.(string) })
+		return items
+	}
+	return yamlScalar(fd, v)
+}
+
+func yamlScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if !v.Message().IsValid() {
+			return nil
+		}
+		return yamlMessage(v.Message())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.Interface()
+}
@@ -0,0 +1,418 @@
+// Command genmock writes mocks of the client and stream interfaces that
+// protoc-gen-go-grpc generates for the services of a proto file. It reads the
+// services from the descriptors registered by the generated packages, so
+// running it after regenerating those keeps the mocks in sync:
+//
+//	genmock -proto greet/greetpb/greet.proto -package greetmock -o mock.go
+//
+// It is run by go generate in the mock packages.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path"
+	"strings"
+	"text/template"
+
+	_ "github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	_ "github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	_ "github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func main() {
+	protoPath := flag.String("proto", "", "path of the proto file, as registered by its generated package")
+	pkg := flag.String("package", "", "name of the mock package")
+	out := flag.String("o", "mock.go", "file to write")
+	flag.Parse()
+
+	src, err := generate(*protoPath, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type file struct {
+	Source    string
+	Package   string
+	PBImport  string
+	PB        string
+	Services  []service
+	Streaming bool
+}
+
+type service struct {
+	Name    string
+	Methods []method
+}
+
+type method struct {
+	Service string
+	Name    string
+	Input   string
+	Output  string
+	// Client and Server streaming.
+	Client, Server bool
+}
+
+// Stream is the name of the stream types of the method, without the Client
+// or Server suffix.
+func (m method) Stream() string {
+	return m.Service + "_" + m.Name
+}
+
+// generate returns the mock package for the proto file at protoPath.
+func generate(protoPath, pkg string) ([]byte, error) {
+	fd, err := protoregistry.GlobalFiles.FindFileByPath(protoPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not registered: %v", protoPath, err)
+	}
+	goPackage := fd.Options().(*descriptorpb.FileOptions).GetGoPackage()
+	if goPackage == "" {
+		return nil, fmt.Errorf("%s has no go_package option", protoPath)
+	}
+	goPackage = strings.SplitN(goPackage, ";", 2)[0]
+
+	f := file{
+		Source:   protoPath,
+		Package:  pkg,
+		PBImport: goPackage,
+		PB:       path.Base(goPackage),
+	}
+	services := fd.Services()
+	for i := 0; i < services.Len(); i++ {
+		sd := services.Get(i)
+		s := service{Name: string(sd.Name())}
+		methods := sd.Methods()
+		for j := 0; j < methods.Len(); j++ {
+			md := methods.Get(j)
+			m := method{
+				Service: s.Name,
+				Name:    string(md.Name()),
+				Input:   f.PB + "." + goName(fd, md.Input()),
+				Output:  f.PB + "." + goName(fd, md.Output()),
+				Client:  md.IsStreamingClient(),
+				Server:  md.IsStreamingServer(),
+			}
+			f.Streaming = f.Streaming || m.Client || m.Server
+			s.Methods = append(s.Methods, m)
+		}
+		f.Services = append(f.Services, s)
+	}
+
+	var buf bytes.Buffer
+	if err := mockTemplate.Execute(&buf, f); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting mocks: %v\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// goName is the name protoc-gen-go gives the message: nested messages are
+// joined to their parents with underscores.
+func goName(fd protoreflect.FileDescriptor, msg protoreflect.MessageDescriptor) string {
+	name := strings.TrimPrefix(string(msg.FullName()), string(fd.Package())+".")
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+var mockTemplate = template.Must(template.New("mock").Parse(`// Code generated by genmock. DO NOT EDIT.
+// source: {{.Source}}
+
+package {{.Package}}
+
+import (
+	"context"
+{{- if .Streaming}}
+	"io"
+{{- end}}
+	"sync"
+
+	"{{.PBImport}}"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Call is a call recorded by a client mock.
+type Call struct {
+	// Method is the name of the method called, such as "{{(index (index .Services 0).Methods 0).Name}}".
+	Method string
+	// Request is the request of a unary or server streaming call, and nil
+	// for calls that send a stream.
+	Request proto.Message
+	// Metadata is the outgoing metadata of the call's context.
+	Metadata metadata.MD
+}
+
+// calls records the calls of a client mock.
+type calls struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (c *calls) record(ctx context.Context, method string, req proto.Message) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Request: req, Metadata: md.Copy()})
+}
+
+// Calls returns the calls made so far, in order.
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+func unprogrammed(method string) error {
+	return status.Errorf(codes.Unimplemented, "mock: no response programmed for %s", method)
+}
+{{range .Services}}{{$service := .}}
+// {{.Name}}Client is a mock {{$.PB}}.{{.Name}}Client.
+//
+// Each method calls the function of the same name with a Func suffix, and
+// fails with UNIMPLEMENTED when that is nil. Calls are recorded either way.
+type {{.Name}}Client struct {
+	calls
+{{range .Methods}}
+	{{.Name}}Func func(ctx context.Context{{if not .Client}}, in *{{.Input}}{{end}}, opts ...grpc.CallOption) ({{if or .Client .Server}}{{$.PB}}.{{.Stream}}Client{{else}}*{{.Output}}{{end}}, error)
+{{- end}}
+}
+
+var _ {{$.PB}}.{{.Name}}Client = (*{{.Name}}Client)(nil)
+{{range .Methods}}
+func (m *{{$service.Name}}Client) {{.Name}}(ctx context.Context{{if not .Client}}, in *{{.Input}}{{end}}, opts ...grpc.CallOption) ({{if or .Client .Server}}{{$.PB}}.{{.Stream}}Client{{else}}*{{.Output}}{{end}}, error) {
+	m.record(ctx, "{{.Name}}", {{if .Client}}nil{{else}}in{{end}})
+	if m.{{.Name}}Func == nil {
+		return nil, unprogrammed("{{.Name}}")
+	}
+	return m.{{.Name}}Func(ctx{{if not .Client}}, in{{end}}, opts...)
+}
+{{end}}{{end}}
+{{- if .Streaming}}
+// ClientStream implements grpc.ClientStream for the client stream mocks.
+// The messages of a stream go through its typed methods, so SendMsg and
+// RecvMsg fail.
+type ClientStream struct {
+	// Ctx is returned by Context; context.Background() when nil.
+	Ctx context.Context
+	// HeaderMD and HeaderErr are returned by Header.
+	HeaderMD  metadata.MD
+	HeaderErr error
+	// TrailerMD is returned by Trailer.
+	TrailerMD metadata.MD
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *ClientStream) Header() (metadata.MD, error) { return s.HeaderMD, s.HeaderErr }
+func (s *ClientStream) Trailer() metadata.MD         { return s.TrailerMD }
+
+func (s *ClientStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+// CloseSend marks the sending side closed.
+func (s *ClientStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// Closed reports whether the client closed its sending side.
+func (s *ClientStream) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *ClientStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ClientStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+
+// ServerStream implements grpc.ServerStream for the server stream mocks,
+// recording the metadata a handler sets.
+type ServerStream struct {
+	// Ctx is returned by Context; context.Background() when nil. Incoming
+	// metadata for the handler goes in it.
+	Ctx context.Context
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *ServerStream) Context() context.Context {
+	if s.Ctx == nil {
+		return context.Background()
+	}
+	return s.Ctx
+}
+
+func (s *ServerStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *ServerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *ServerStream) SetTrailer(md metadata.MD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// Header returns the header metadata the handler set or sent.
+func (s *ServerStream) Header() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header.Copy()
+}
+
+// Trailer returns the trailer metadata the handler set.
+func (s *ServerStream) Trailer() metadata.MD {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trailer.Copy()
+}
+
+func (s *ServerStream) SendMsg(m interface{}) error { return unprogrammed("SendMsg") }
+func (s *ServerStream) RecvMsg(m interface{}) error { return unprogrammed("RecvMsg") }
+{{range .Services}}{{range .Methods}}{{if or .Client .Server}}
+// {{.Stream}}Client is a mock {{$.PB}}.{{.Stream}}Client.
+type {{.Stream}}Client struct {
+	ClientStream
+{{- if .Server}}
+	// Responses are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Responses []*{{.Output}}
+{{- else}}
+	// Response and Err are returned by CloseAndRecv.
+	Response *{{.Output}}
+{{- end}}
+	Err error
+{{- if .Client}}
+	// SendErr is returned by Send without recording the message.
+	SendErr error
+
+	sent []*{{.Input}}
+{{- end}}
+{{- if .Server}}
+	received int
+{{- end}}
+}
+
+var _ {{$.PB}}.{{.Stream}}Client = (*{{.Stream}}Client)(nil)
+{{if .Client}}
+func (s *{{.Stream}}Client) Send(m *{{.Input}}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far.
+func (s *{{.Stream}}Client) Sent() []*{{.Input}} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*{{.Input}}(nil), s.sent...)
+}
+{{end}}{{if .Server}}
+func (s *{{.Stream}}Client) Recv() (*{{.Output}}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Responses) {
+		s.received++
+		return s.Responses[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+{{else}}
+func (s *{{.Stream}}Client) CloseAndRecv() (*{{.Output}}, error) {
+	s.CloseSend()
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return s.Response, nil
+}
+{{end}}
+// {{.Stream}}Server is a mock {{$.PB}}.{{.Stream}}Server.
+// Handlers can be called with it directly.
+type {{.Stream}}Server struct {
+	ServerStream
+{{- if .Client}}
+	// Requests are returned by Recv in order, followed by Err, or io.EOF
+	// when Err is nil.
+	Requests []*{{.Input}}
+	Err      error
+{{- end}}
+	// SendErr is returned by {{if .Server}}Send{{else}}SendAndClose{{end}} without recording the message.
+	SendErr error
+
+	sent []*{{.Output}}
+{{- if .Client}}
+	received int
+{{- end}}
+}
+
+var _ {{$.PB}}.{{.Stream}}Server = (*{{.Stream}}Server)(nil)
+{{if .Client}}
+func (s *{{.Stream}}Server) Recv() (*{{.Input}}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.received < len(s.Requests) {
+		s.received++
+		return s.Requests[s.received-1], nil
+	}
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	return nil, io.EOF
+}
+{{end}}
+func (s *{{.Stream}}Server) {{if .Server}}Send{{else}}SendAndClose{{end}}(m *{{.Output}}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SendErr != nil {
+		return s.SendErr
+	}
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+// Sent returns the {{if .Server}}messages sent so far{{else}}response sent with SendAndClose, if any{{end}}.
+func (s *{{.Stream}}Server) Sent() []*{{.Output}} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*{{.Output}}(nil), s.sent...)
+}
+{{end}}{{end}}{{end}}{{end}}`))
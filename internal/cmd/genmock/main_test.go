@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerated fails when a mock package is out of date with its proto
+// file; run go generate in it.
+func TestGenerated(t *testing.T) {
+	tests := []struct {
+		proto, pkg, file string
+	}{
+		{"greet/greetpb/greet.proto", "greetmock", "../../../greet/greetmock/mock.go"},
+		{"calculator/calculatorpb/calculator.proto", "calculatormock", "../../../calculator/calculatormock/mock.go"},
+		{"blog/blogpb/blog.proto", "blogmock", "../../../blog/blogmock/mock.go"},
+	}
+	for _, tt := range tests {
+		want, err := generate(tt.proto, tt.pkg)
+		if err != nil {
+			t.Fatalf("generating %s: %v", tt.pkg, err)
+		}
+		got, err := ioutil.ReadFile(filepath.FromSlash(tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date with %s; run go generate in %s", tt.file, tt.proto, filepath.Dir(tt.file))
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+)
+
+// TestGenerated fails when the generated code is out of date with the proto
+// files or the plugin versions in go.mod; run protogen. It needs protoc
+// protocVersion on the PATH.
+func TestGenerated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the protoc plugins")
+	}
+	out, err := exec.Command("protoc", "--version").Output()
+	if err != nil || string(out) != "libprotoc "+protocVersion+"\n" {
+		t.Skipf("needs protoc %s on the PATH", protocVersion)
+	}
+	if err := checkGenerated("../../..", "protoc"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+// Package compression registers the message compressors the course servers
+// and clients support, gzip and snappy, under their standard grpc-encoding
+// names. Importing it is enough for a server to answer compressed calls in
+// kind and for a client to ask for them with grpc.UseCompressor.
+//
+// gRPC picks the compressor of a response from the request, so servers
+// cannot choose per message which responses are worth compressing. Instead
+// messages smaller than the minimum size, DefaultMinSize unless configured,
+// are written in the stored form of their encoding: any gzip or snappy
+// reader accepts them, but they cost no compression time and barely grow.
+//
+// The minimum size is a setting of the whole process, not of one server:
+// gRPC registers compressors globally by name and hands them only the
+// message, so the same compressor serves every server and client
+// connection in the process and cannot tell them apart.
+package compression
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// The names of the compressors, as sent in the grpc-encoding header.
+const (
+	Gzip   = "gzip"
+	Snappy = "snappy"
+	// Identity sends messages uncompressed, overriding a connection default.
+	Identity = encoding.Identity
+)
+
+// Names lists the compressors a call may ask for, Identity included.
+var Names = []string{Identity, Gzip, Snappy}
+
+// DefaultMinSize is the smallest message compressed unless configured:
+// below about a kilobyte the framing and CPU time outweigh the savings.
+const DefaultMinSize = 1024
+
+// Config is the compression configuration of a process. See Configure.
+type Config struct {
+	// MinSize is the size in bytes from which messages are compressed;
+	// smaller ones are stored. Zero compresses every message.
+	MinSize int
+}
+
+// RegisterFlags adds the -compress-min-size flag to fs, defaulting to
+// cfg's value.
+func RegisterFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.IntVar(&cfg.MinSize, "compress-min-size", cfg.MinSize, "messages smaller than this many bytes are stored rather than compressed, even for callers that asked for compression; 0 compresses all of them. The setting is process-wide: it applies to every server and client connection of the process")
+}
+
+// Configure applies cfg to every message the process sends from now on, on
+// all of its servers and client connections alike. Tests that call it must
+// restore DefaultMinSize and not run in parallel with other compressed
+// calls.
+func Configure(cfg Config) {
+	atomic.StoreInt64(&minSize, int64(cfg.MinSize))
+}
+
+var minSize int64 = DefaultMinSize
+
+func init() {
+	encoding.RegisterCompressor(&compressor{name: Gzip, codec: gzipCodec{}})
+	encoding.RegisterCompressor(&compressor{name: Snappy, codec: snappyCodec{}})
+}
+
+// IsValid reports whether name is one of Names.
+func IsValid(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// codec is the format of one encoding.
+type codec interface {
+	// compress writes p compressed to w.
+	compress(w io.Writer, p []byte) error
+	// store writes p to w in the format without compressing it.
+	store(w io.Writer, p []byte) error
+	decompress(r io.Reader) (io.Reader, error)
+}
+
+// compressor is an encoding.Compressor that collects a message before
+// deciding whether it is worth compressing.
+type compressor struct {
+	name  string
+	codec codec
+}
+
+var buffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+func (c *compressor) Name() string { return c.name }
+
+func (c *compressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	buf := buffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	return &messageWriter{w: w, buf: buf, codec: c.codec}, nil
+}
+
+func (c *compressor) Decompress(r io.Reader) (io.Reader, error) {
+	return c.codec.decompress(r)
+}
+
+// messageWriter buffers a message and writes it encoded on Close.
+type messageWriter struct {
+	w     io.Writer
+	buf   *bytes.Buffer
+	codec codec
+}
+
+func (m *messageWriter) Write(p []byte) (int, error) {
+	return m.buf.Write(p)
+}
+
+func (m *messageWriter) Close() error {
+	defer buffers.Put(m.buf)
+	msg := m.buf.Bytes()
+	if int64(len(msg)) < atomic.LoadInt64(&minSize) {
+		return m.codec.store(m.w, msg)
+	}
+	return m.codec.compress(m.w, msg)
+}
@@ -0,0 +1,131 @@
+package compression_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/client"
+	"github.com/pandadragoon/grpc-go-course/internal/compression"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// text is compressible data of n bytes.
+func text(n int) []byte {
+	return []byte(strings.Repeat("all work and no play makes jack a dull boy ", n/43+1)[:n])
+}
+
+func encode(t *testing.T, name string, msg []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := encoding.GetCompressor(name).Compress(&buf)
+	if err != nil {
+		t.Fatalf("%s: Compress: %v", name, err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		t.Fatalf("%s: Write: %v", name, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("%s: Close: %v", name, err)
+	}
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+	r, err := encoding.GetCompressor(name).Decompress(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: Decompress: %v", name, err)
+	}
+	msg, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%s: reading: %v", name, err)
+	}
+	return msg
+}
+
+func TestRoundTrip(t *testing.T) {
+	sizes := []int{0, 10, compression.DefaultMinSize - 1, compression.DefaultMinSize, 200000}
+	for _, name := range []string{compression.Gzip, compression.Snappy} {
+		for _, size := range sizes {
+			msg := text(size)
+			data := encode(t, name, msg)
+			if got := decode(t, name, data); !bytes.Equal(got, msg) {
+				t.Errorf("%s, %d bytes: round trip changed the message", name, size)
+			}
+			stored := len(data) >= size
+			if want := size < compression.DefaultMinSize; stored != want {
+				t.Errorf("%s, %d bytes: encoded to %d bytes, want stored %v", name, size, len(data), want)
+			}
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { compression.Configure(compression.Config{MinSize: compression.DefaultMinSize}) })
+	msg := text(900)
+
+	compression.Configure(compression.Config{MinSize: 0})
+	if data := encode(t, compression.Gzip, msg); len(data) >= len(msg) {
+		t.Errorf("MinSize 0: %d bytes encoded to %d, want them compressed", len(msg), len(data))
+	}
+	compression.Configure(compression.Config{MinSize: 1 << 20})
+	if data := encode(t, compression.Gzip, text(100000)); len(data) < 100000 {
+		t.Errorf("MinSize 1MiB: 100000 bytes encoded to %d, want them stored", len(data))
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	for _, name := range []string{compression.Gzip, compression.Snappy} {
+		data := encode(t, name, text(5000))
+		data[len(data)/2] ^= 0xff
+		r, err := encoding.GetCompressor(name).Decompress(bytes.NewReader(data))
+		if err == nil {
+			_, err = ioutil.ReadAll(r)
+		}
+		if err == nil {
+			t.Errorf("%s: corrupt message decoded without error", name)
+		}
+	}
+}
+
+// TestListBlog checks that large blogs are compressed on the wire and
+// small ones are not, whichever compressor the call asks for.
+func TestListBlog(t *testing.T) {
+	for _, name := range compression.Names {
+		for _, size := range []int{100, 50000} {
+			payloads := new(servertest.Payloads)
+			c, store := servertest.Blog(t, servertest.WithDialOptions(grpc.WithStatsHandler(payloads)))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if _, err := store.Create(ctx, &blogpb.Blog{AuthorId: "ada", Title: "Notes", Content: string(text(size))}); err != nil {
+				t.Fatalf("creating blog: %v", err)
+			}
+			stream, err := c.ListBlog(ctx, &blogpb.ListBlogRequest{}, client.UseCompression(name))
+			if err != nil {
+				t.Fatalf("%s: ListBlog: %v", name, err)
+			}
+			for {
+				if _, err := stream.Recv(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("%s: ListBlog Recv: %v", name, err)
+				}
+			}
+
+			_, length, onWire := payloads.Received()
+			compressed := onWire < length
+			if want := name != compression.Identity && size >= compression.DefaultMinSize; compressed != want {
+				t.Errorf("%s, %d byte blog: received %d bytes in %d on the wire, want compressed %v", name, size, length, onWire, want)
+			}
+		}
+	}
+}
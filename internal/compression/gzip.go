@@ -0,0 +1,70 @@
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// gzipCodec compresses at the default level and stores with level 0, which
+// writes the message as uncompressed deflate blocks.
+type gzipCodec struct{}
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(ioutil.Discard)
+	}}
+	gzipStoreWriters = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(ioutil.Discard, gzip.NoCompression)
+		return w
+	}}
+	gzipReaders sync.Pool
+)
+
+func (gzipCodec) compress(w io.Writer, p []byte) error {
+	return writeGzip(&gzipWriters, w, p)
+}
+
+func (gzipCodec) store(w io.Writer, p []byte) error {
+	return writeGzip(&gzipStoreWriters, w, p)
+}
+
+func writeGzip(pool *sync.Pool, w io.Writer, p []byte) error {
+	z := pool.Get().(*gzip.Writer)
+	defer pool.Put(z)
+	z.Reset(w)
+	if _, err := z.Write(p); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+func (gzipCodec) decompress(r io.Reader) (io.Reader, error) {
+	z, ok := gzipReaders.Get().(*gzip.Reader)
+	if !ok {
+		z, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &gzipReader{z}, nil
+	}
+	if err := z.Reset(r); err != nil {
+		gzipReaders.Put(z)
+		return nil, err
+	}
+	return &gzipReader{z}, nil
+}
+
+// gzipReader returns its reader to the pool at the end of the message.
+type gzipReader struct {
+	*gzip.Reader
+}
+
+func (z *gzipReader) Read(p []byte) (int, error) {
+	n, err := z.Reader.Read(p)
+	if err == io.EOF {
+		gzipReaders.Put(z.Reader)
+	}
+	return n, err
+}
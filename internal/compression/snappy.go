@@ -0,0 +1,93 @@
+package compression
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/golang/snappy"
+)
+
+// snappyCodec uses the snappy framing format, whose uncompressed chunks
+// store messages.
+type snappyCodec struct{}
+
+var (
+	snappyWriters = sync.Pool{New: func() interface{} {
+		return snappy.NewWriter(ioutil.Discard)
+	}}
+	snappyReaders = sync.Pool{New: func() interface{} {
+		return snappy.NewReader(nil)
+	}}
+)
+
+func (snappyCodec) compress(w io.Writer, p []byte) error {
+	z := snappyWriters.Get().(*snappy.Writer)
+	defer snappyWriters.Put(z)
+	z.Reset(w)
+	if _, err := z.Write(p); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+const (
+	// snappyStreamID starts every snappy stream.
+	snappyStreamID = "\xff\x06\x00\x00sNaPpY"
+	// snappyUncompressed is the type of uncompressed chunks.
+	snappyUncompressed = 0x01
+	// snappyMaxChunk is the most data one chunk may carry.
+	snappyMaxChunk = 65536
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+func (snappyCodec) store(w io.Writer, p []byte) error {
+	if _, err := io.WriteString(w, snappyStreamID); err != nil {
+		return err
+	}
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > snappyMaxChunk {
+			chunk = chunk[:snappyMaxChunk]
+		}
+		p = p[len(chunk):]
+
+		// The chunk length covers the checksum, which is masked as the
+		// format requires.
+		var header [8]byte
+		n := len(chunk) + 4
+		header[0] = snappyUncompressed
+		header[1], header[2], header[3] = byte(n), byte(n>>8), byte(n>>16)
+		c := crc32.Update(0, castagnoli, chunk)
+		binary.LittleEndian.PutUint32(header[4:], (c>>15|c<<17)+0xa282ead8)
+		if _, err := w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (snappyCodec) decompress(r io.Reader) (io.Reader, error) {
+	z := snappyReaders.Get().(*snappy.Reader)
+	z.Reset(r)
+	return &snappyReader{z}, nil
+}
+
+// snappyReader returns its reader to the pool at the end of the message.
+type snappyReader struct {
+	*snappy.Reader
+}
+
+func (z *snappyReader) Read(p []byte) (int, error) {
+	n, err := z.Reader.Read(p)
+	if err == io.EOF {
+		snappyReaders.Put(z.Reader)
+	}
+	return n, err
+}
@@ -0,0 +1,79 @@
+// Package deadline provides a server option that gives calls without a
+// deadline a default one and cuts overly long deadlines down to a maximum,
+// so no call can hold server resources indefinitely. The deadline is set on
+// the call's own context before gRPC hands it to interceptors, so handlers
+// see it as usual and gRPC ends pending receives when it passes, exactly as
+// for a deadline the client sent. Health checking and reflection are left
+// alone.
+package deadline
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/internal/grpcinfra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/tap"
+)
+
+// Limits bound the deadline of a call. Zero values impose nothing.
+type Limits struct {
+	// Default is the deadline of calls that arrive without one.
+	Default time.Duration
+	// Max caps the deadline of every call.
+	Max time.Duration
+}
+
+// Config configures ServerOption.
+type Config struct {
+	// Limits apply to every method not in Methods.
+	Limits
+	// Methods overrides Limits for full method names such as
+	// "/blog.BlogService/ListBlog".
+	Methods map[string]Limits
+}
+
+// RegisterFlags adds -default-deadline and -max-deadline to fs, setting the
+// Limits of cfg. The values already in cfg are the flag defaults.
+func RegisterFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.DurationVar(&cfg.Default, "default-deadline", cfg.Default, "deadline of calls that arrive without one; 0 for none")
+	fs.DurationVar(&cfg.Max, "max-deadline", cfg.Max, "longest deadline a call may have; 0 for no limit")
+}
+
+func (c *Config) limits(method string) Limits {
+	if l, ok := c.Methods[method]; ok {
+		return l
+	}
+	return c.Limits
+}
+
+// context applies the limits for method to ctx.
+func (c *Config) context(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	l := c.limits(method)
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok && l.Default > 0:
+		if l.Max > 0 && l.Max < l.Default {
+			return context.WithTimeout(ctx, l.Max)
+		}
+		return context.WithTimeout(ctx, l.Default)
+	case l.Max > 0 && (!ok || time.Until(deadline) > l.Max):
+		return context.WithTimeout(ctx, l.Max)
+	}
+	return ctx, func() {}
+}
+
+// ServerOption applies cfg to every call of the server. It takes the
+// server's tap handle, which gRPC lets only one option set.
+func ServerOption(cfg Config) grpc.ServerOption {
+	return grpc.InTapHandle(func(ctx context.Context, info *tap.Info) (context.Context, error) {
+		if grpcinfra.IsInfrastructure(info.FullMethodName) {
+			return ctx, nil
+		}
+		// gRPC cancels the context of a call when the call ends, which
+		// releases the shortened one derived from it.
+		ctx, _ = cfg.context(ctx, info.FullMethodName)
+		return ctx, nil
+	})
+}
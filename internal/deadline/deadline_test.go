@@ -0,0 +1,156 @@
+package deadline_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/internal/deadline"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// probe answers Greet with the time left until the deadline of the call,
+// and reports how the receive of a GreetEveryone stream ended.
+type probe struct {
+	greetpb.UnimplementedGreetServiceServer
+	healthpb.UnimplementedHealthServer
+	received chan error
+}
+
+func remaining(ctx context.Context) string {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "none"
+	}
+	return time.Until(deadline).Round(time.Minute).String()
+}
+
+func (probe) Greet(ctx context.Context, _ *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	return &greetpb.GreetResponse{Result: remaining(ctx)}, nil
+}
+
+func (p probe) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
+	_, err := stream.Recv()
+	p.received <- err
+	return err
+}
+
+// Check serves only calls without a deadline.
+func (probe) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if remaining(ctx) != "none" {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func start(t *testing.T, cfg deadline.Config) (*grpc.ClientConn, probe) {
+	p := probe{received: make(chan error, 1)}
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, p)
+		healthpb.RegisterHealthServer(s, p)
+	}, servertest.WithServerOptions(deadline.ServerOption(cfg)))
+	return conn, p
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  deadline.Limits
+		timeout time.Duration // 0 for a call without a deadline
+		want    string
+	}{
+		{"default", deadline.Limits{Default: time.Hour}, 0, "1h0m0s"},
+		{"default kept within max", deadline.Limits{Default: time.Hour, Max: 10 * time.Minute}, 0, "10m0s"},
+		{"client deadline kept", deadline.Limits{Default: time.Hour}, 2 * time.Hour, "2h0m0s"},
+		{"client deadline clamped", deadline.Limits{Max: 10 * time.Minute}, time.Hour, "10m0s"},
+		{"client deadline within max", deadline.Limits{Max: 10 * time.Minute}, 5 * time.Minute, "5m0s"},
+		{"max without a client deadline", deadline.Limits{Max: 10 * time.Minute}, 0, "10m0s"},
+		{"no limits", deadline.Limits{}, 0, "none"},
+	}
+	for _, tt := range tests {
+		conn, _ := start(t, deadline.Config{Limits: tt.limits})
+		ctx := context.Background()
+		if tt.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+			defer cancel()
+		}
+		res, err := greetpb.NewGreetServiceClient(conn).Greet(ctx, &greetpb.GreetRequest{})
+		if err != nil {
+			t.Fatalf("%s: Greet: %v", tt.name, err)
+		}
+		if res.GetResult() != tt.want {
+			t.Errorf("%s: handler deadline in %s, want %s", tt.name, res.GetResult(), tt.want)
+		}
+	}
+}
+
+func TestMethods(t *testing.T) {
+	conn, _ := start(t, deadline.Config{
+		Limits:  deadline.Limits{Default: time.Hour, Max: time.Hour},
+		Methods: map[string]deadline.Limits{"/greet.GreetService/Greet": {Default: 10 * time.Minute}},
+	})
+	c := greetpb.NewGreetServiceClient(conn)
+	res, err := c.Greet(context.Background(), &greetpb.GreetRequest{})
+	if err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if res.GetResult() != "10m0s" {
+		t.Errorf("without a client deadline: handler deadline in %s, want 10m0s", res.GetResult())
+	}
+
+	// The method's limits replace the others as a whole, max included.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	res, err = c.Greet(ctx, &greetpb.GreetRequest{})
+	if err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	if res.GetResult() != "2h0m0s" {
+		t.Errorf("with a client deadline: handler deadline in %s, want 2h0m0s", res.GetResult())
+	}
+}
+
+func TestInfrastructure(t *testing.T) {
+	conn, _ := start(t, deadline.Config{Limits: deadline.Limits{Default: time.Hour}})
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("health check got a deadline")
+	}
+}
+
+// TestStreamExpiry checks that a handler waiting for a message that never
+// comes gives up when the deadline passes, and the client hears so.
+func TestStreamExpiry(t *testing.T) {
+	const max = 50 * time.Millisecond
+	conn, p := start(t, deadline.Config{Limits: deadline.Limits{Max: max}})
+	began := time.Now()
+	stream, err := greetpb.NewGreetServiceClient(conn).GreetEveryone(testContext(t))
+	if err != nil {
+		t.Fatalf("GreetEveryone: %v", err)
+	}
+
+	if err := <-p.received; status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("handler Recv: got %v, want %v", err, codes.DeadlineExceeded)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("client Recv: got %v, want %v", err, codes.DeadlineExceeded)
+	}
+	if elapsed := time.Since(began); elapsed < max || elapsed > 5*time.Second {
+		t.Errorf("stream ended after %v, want about %v", elapsed, max)
+	}
+}
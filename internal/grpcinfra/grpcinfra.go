@@ -0,0 +1,47 @@
+// Package grpcinfra holds what every server sets up for its own operation,
+// apart from the application services: the infrastructure services it
+// registers, such as health checking, and the keepalive policy it enforces.
+package grpcinfra
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+var services = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// IsInfrastructure reports whether fullMethod, such as
+// "/grpc.health.v1.Health/Watch", belongs to an infrastructure service.
+// Interceptors that protect application methods skip these: clients hold a
+// health watch open on every connection, and cutting it off would take
+// healthy backends out of rotation.
+func IsInfrastructure(fullMethod string) bool {
+	for _, prefix := range services {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// minPingInterval is the shortest interval between client pings that
+// servers accept.
+const minPingInterval = 20 * time.Second
+
+// KeepaliveEnforcement accepts the keepalive pings of clients using the
+// client package, which ping every 30s while streams are open. Servers drop
+// connections that ping more often than MinTime, so it stays well below
+// that; pings without streams are allowed since health watches keep one
+// open on every connection anyway.
+func KeepaliveEnforcement() grpc.ServerOption {
+	return grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime:             minPingInterval,
+		PermitWithoutStream: true,
+	})
+}
@@ -0,0 +1,32 @@
+package grpcinfra
+
+import (
+	"testing"
+
+	"github.com/pandadragoon/grpc-go-course/client"
+)
+
+func TestIsInfrastructure(t *testing.T) {
+	for method, want := range map[string]bool{
+		"/grpc.health.v1.Health/Check":                                   true,
+		"/grpc.health.v1.Health/Watch":                                   true,
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": true,
+		"/greet.GreetService/Greet":                                      false,
+		"/grpc.health.v1.HealthCheck/Check":                              false,
+		"/grpc.health.v1.Health":                                         false,
+		"":                                                               false,
+	} {
+		if got := IsInfrastructure(method); got != want {
+			t.Errorf("IsInfrastructure(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+// TestKeepalive checks that servers accept the pings of clients with the
+// default keepalive, which they would otherwise disconnect with
+// ENHANCE_YOUR_CALM.
+func TestKeepalive(t *testing.T) {
+	if client.DefaultKeepalive.Time < minPingInterval {
+		t.Errorf("clients ping every %v, but servers only accept pings at least %v apart", client.DefaultKeepalive.Time, minPingInterval)
+	}
+}
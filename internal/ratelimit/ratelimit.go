@@ -0,0 +1,302 @@
+// Package ratelimit provides server interceptors that limit how fast each
+// caller may call each method, and how many streams a caller may keep open
+// at once. Health checking and reflection are not limited. Rejected calls
+// fail with RESOURCE_EXHAUSTED, a RetryInfo detail saying when to try again,
+// and the grpc-retry-pushback-ms trailer that gRPC clients with a retry
+// policy honour on their own.
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/internal/grpcinfra"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIDKey is the metadata key callers name themselves with. The client
+// package sets it when given a client ID. Anyone can send any ID, so it only
+// labels rejections and never decides which limits apply.
+const ClientIDKey = "x-client-id"
+
+// ErrorInfo reasons of rejected calls, in the domain of the called service.
+const (
+	ReasonRateLimited    = "RATE_LIMITED"
+	ReasonTooManyStreams = "TOO_MANY_STREAMS"
+)
+
+// pushbackKey is the trailer gRPC retry policies read the retry delay from.
+const pushbackKey = "grpc-retry-pushback-ms"
+
+// Limit is a token bucket: calls are allowed at Rate per second on average,
+// with up to Burst at once. The zero Limit allows everything.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) unlimited() bool {
+	return l.Rate <= 0
+}
+
+func (l Limit) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// Config configures a Limiter.
+type Config struct {
+	// Default limits each caller on every method not in Methods. When it
+	// is unlimited, so is every method.
+	Default Limit
+	// Methods overrides Default for full method names such as
+	// "/calculator.CalculatorService/PrimeNumberDecomposition".
+	Methods map[string]Limit
+	// MaxStreams bounds the streams each caller may have open at once;
+	// 0 means no bound.
+	MaxStreams int
+	// StreamRetryDelay is the delay suggested to callers over MaxStreams,
+	// one second when zero.
+	StreamRetryDelay time.Duration
+}
+
+// Limiter enforces a Config. Its interceptors share state, so register both
+// from the same Limiter.
+type Limiter struct {
+	cfg Config
+
+	// now is time.Now, replaced by tests.
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	streams   map[string]int
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	caller, method string
+}
+
+// RegisterFlags adds -rate-limit, -rate-burst and -max-streams to fs,
+// setting the Default limit and MaxStreams of cfg. The values already in cfg
+// are the flag defaults.
+func RegisterFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.Float64Var(&cfg.Default.Rate, "rate-limit", cfg.Default.Rate, "calls per second each caller may make to each method; 0 disables rate limiting")
+	fs.IntVar(&cfg.Default.Burst, "rate-burst", cfg.Default.Burst, "calls each caller may make at once above -rate-limit")
+	fs.IntVar(&cfg.MaxStreams, "max-streams", cfg.MaxStreams, "streams each caller may have open at once; 0 for no limit")
+}
+
+// New returns a Limiter for cfg.
+func New(cfg Config) *Limiter {
+	if cfg.StreamRetryDelay <= 0 {
+		cfg.StreamRetryDelay = time.Second
+	}
+	return &Limiter{
+		cfg:       cfg,
+		now:       time.Now,
+		buckets:   make(map[bucketKey]*bucket),
+		streams:   make(map[string]int),
+		lastSweep: time.Now(),
+	}
+}
+
+// UnaryServerInterceptor applies the rate limits to unary calls.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if grpcinfra.IsInfrastructure(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if wait, ok := l.allow(Caller(ctx), info.FullMethod); !ok {
+			grpc.SetTrailer(ctx, pushback(wait))
+			return nil, exhausted(ctx, info.FullMethod, ReasonRateLimited, "rate limit exceeded for "+info.FullMethod, wait)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor applies the rate limits and MaxStreams to
+// streaming calls.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if grpcinfra.IsInfrastructure(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx := ss.Context()
+		caller := Caller(ctx)
+		if wait, ok := l.allow(caller, info.FullMethod); !ok {
+			ss.SetTrailer(pushback(wait))
+			return exhausted(ctx, info.FullMethod, ReasonRateLimited, "rate limit exceeded for "+info.FullMethod, wait)
+		}
+		if !l.openStream(caller) {
+			ss.SetTrailer(pushback(l.cfg.StreamRetryDelay))
+			return exhausted(ctx, info.FullMethod, ReasonTooManyStreams, "too many concurrent streams", l.cfg.StreamRetryDelay)
+		}
+		defer l.closeStream(caller)
+		return handler(srv, ss)
+	}
+}
+
+// allow takes a token for the call, or returns how long until one is
+// available.
+func (l *Limiter) allow(caller, method string) (time.Duration, bool) {
+	if l.cfg.Default.unlimited() {
+		return 0, true
+	}
+	limit, ok := l.cfg.Methods[method]
+	if !ok {
+		limit = l.cfg.Default
+	}
+	if limit.unlimited() {
+		return 0, true
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	key := bucketKey{caller, method}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.burst()), last: now}
+		l.buckets[key] = b
+	}
+	return b.take(now)
+}
+
+// sweep drops the buckets that have refilled completely, which behave
+// exactly like new ones, so idle callers do not accumulate.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.full(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) openStream(caller string) bool {
+	if l.cfg.MaxStreams <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[caller] >= l.cfg.MaxStreams {
+		return false
+	}
+	l.streams[caller]++
+	return true
+}
+
+func (l *Limiter) closeStream(caller string) {
+	if l.cfg.MaxStreams <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[caller]--; l.streams[caller] <= 0 {
+		delete(l.streams, caller)
+	}
+}
+
+// Caller identifies the caller of ctx by what the caller cannot choose: the
+// subject of its verified TLS client certificate, otherwise the host of its
+// peer address. Callers behind one address share their limits.
+func Caller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		if cert := verifiedLeaf(info.State); cert != "" {
+			return "cert:" + cert
+		}
+	}
+	if p.Addr == nil {
+		return "unknown"
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return "addr:" + addr
+}
+
+func verifiedLeaf(state tls.ConnectionState) string {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.String()
+}
+
+// clientID returns the x-client-id metadata of ctx, if any.
+func clientID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(ClientIDKey); len(ids) > 0 {
+			return ids[0]
+		}
+	}
+	return ""
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	if max := float64(b.limit.burst()); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+}
+
+func (b *bucket) take(now time.Time) (time.Duration, bool) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	wait := time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+	return wait, false
+}
+
+func (b *bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.limit.burst())
+}
+
+func pushback(wait time.Duration) metadata.MD {
+	ms := wait.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return metadata.Pairs(pushbackKey, strconv.FormatInt(ms, 10))
+}
+
+// exhausted returns the error of a rejected call, naming the client ID of
+// ctx so that applications sharing an address can tell whose calls used up
+// the limit.
+func exhausted(ctx context.Context, method, reason, msg string, wait time.Duration) error {
+	if id := clientID(ctx); id != "" {
+		msg += " (client " + strconv.Quote(id) + ")"
+	}
+	return rpcerr.MethodDomain(method).Exhausted(reason, msg, wait)
+}
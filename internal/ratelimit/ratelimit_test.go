@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// clock is a time that only moves when told to.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// greet starts a greet service behind a Limiter for cfg whose time moves
+// with the returned clock.
+func greet(t *testing.T, cfg Config) (greetpb.GreetServiceClient, *clock) {
+	l := New(cfg)
+	c := &clock{now: time.Now()}
+	l.now = c.Now
+	client := servertest.Greet(t, servertest.WithServerOptions(
+		grpc.ChainUnaryInterceptor(l.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(l.StreamServerInterceptor()),
+	))
+	return client, c
+}
+
+// checkExhausted checks that err rejects a call for reason, asking to retry
+// after wait in both the RetryInfo detail and the pushback trailer.
+func checkExhausted(t *testing.T, err error, trailer metadata.MD, reason string, wait time.Duration) {
+	t.Helper()
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want %v", err, codes.ResourceExhausted)
+	}
+	d := rpcerr.Decode(err)
+	if got := d.Info.GetReason(); got != reason {
+		t.Errorf("reason %q, want %q", got, reason)
+	}
+	if delay, ok := d.RetryDelay(); !ok || delay != wait {
+		t.Errorf("retry delay %v, %v, want %v", delay, ok, wait)
+	}
+	if got, want := trailer.Get(pushbackKey), strconv.FormatInt(wait.Milliseconds(), 10); len(got) != 1 || got[0] != want {
+		t.Errorf("pushback trailer %v, want %s", got, want)
+	}
+}
+
+func TestRefill(t *testing.T) {
+	c, clk := greet(t, Config{Default: Limit{Rate: 10, Burst: 2}})
+	call := func(id string) (metadata.MD, error) {
+		ctx := testContext(t)
+		if id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, ClientIDKey, id)
+		}
+		var trailer metadata.MD
+		_, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}}, grpc.Trailer(&trailer))
+		return trailer, err
+	}
+
+	// The burst goes through at once, then a token comes every 100ms.
+	for i := 0; i < 2; i++ {
+		if _, err := call(""); err != nil {
+			t.Fatalf("call %d of the burst: %v", i+1, err)
+		}
+	}
+	trailer, err := call("")
+	checkExhausted(t, err, trailer, ReasonRateLimited, 100*time.Millisecond)
+
+	clk.Advance(60 * time.Millisecond)
+	trailer, err = call("")
+	checkExhausted(t, err, trailer, ReasonRateLimited, 40*time.Millisecond)
+
+	// Another client ID is the same caller, only named in the error.
+	trailer, err = call("other-app")
+	checkExhausted(t, err, trailer, ReasonRateLimited, 40*time.Millisecond)
+	if !strings.Contains(err.Error(), `client "other-app"`) {
+		t.Errorf("%v does not name the client", err)
+	}
+
+	clk.Advance(40 * time.Millisecond)
+	if _, err := call(""); err != nil {
+		t.Fatalf("after refilling a token: %v", err)
+	}
+
+	// An idle bucket fills up to the burst and no further.
+	clk.Advance(time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := call(""); err != nil {
+			t.Fatalf("call %d after an hour: %v", i+1, err)
+		}
+	}
+	trailer, err = call("")
+	checkExhausted(t, err, trailer, ReasonRateLimited, 100*time.Millisecond)
+}
+
+func TestMaxStreams(t *testing.T) {
+	c, _ := greet(t, Config{MaxStreams: 1, StreamRetryDelay: 3 * time.Second})
+	open := func() (greetpb.GreetService_GreetEveryoneClient, error) {
+		stream, err := c.GreetEveryone(testContext(t))
+		if err != nil {
+			t.Fatalf("GreetEveryone: %v", err)
+		}
+		if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "Ada"}}); err != nil {
+			t.Fatalf("GreetEveryone Send: %v", err)
+		}
+		_, err = stream.Recv()
+		return stream, err
+	}
+
+	first, err := open()
+	if err != nil {
+		t.Fatalf("first stream: %v", err)
+	}
+	second, err := open()
+	checkExhausted(t, err, second.Trailer(), ReasonTooManyStreams, 3*time.Second)
+
+	// Closing the first stream makes room.
+	first.CloseSend()
+	if _, err := first.Recv(); err != io.EOF {
+		t.Fatalf("first stream: got %v, want io.EOF", err)
+	}
+	third, err := open()
+	if err != nil {
+		t.Fatalf("stream after closing the first: %v", err)
+	}
+	third.CloseSend()
+}
+
+func TestSweep(t *testing.T) {
+	const method, slow = "/greet.GreetService/Greet", "/greet.GreetService/GreetWithDeadline"
+	l := New(Config{
+		Default: Limit{Rate: 10, Burst: 2},
+		Methods: map[string]Limit{slow: {Rate: 0.001, Burst: 2}},
+	})
+	clk := &clock{now: time.Now()}
+	l.now = clk.Now
+	l.lastSweep = clk.Now()
+
+	for _, caller := range []string{"addr:a", "addr:b"} {
+		l.allow(caller, method)
+		l.allow(caller, slow)
+	}
+	if len(l.buckets) != 4 {
+		t.Fatalf("%d buckets, want 4", len(l.buckets))
+	}
+
+	// Sweeps wait a minute, then drop the buckets that refilled, which are
+	// the fast ones.
+	clk.Advance(30 * time.Second)
+	l.allow("addr:c", method)
+	if len(l.buckets) != 5 {
+		t.Errorf("%d buckets before a minute passed, want 5", len(l.buckets))
+	}
+	clk.Advance(31 * time.Second)
+	l.allow("addr:c", slow)
+	for key := range l.buckets {
+		if key.method != slow {
+			t.Errorf("bucket %v survived the sweep", key)
+		}
+	}
+	if len(l.buckets) != 3 {
+		t.Errorf("%d buckets after the sweep, want 3", len(l.buckets))
+	}
+}
+
+func TestCaller(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4321}
+	alice := &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}}
+	tests := []struct {
+		name string
+		peer *peer.Peer
+		want string
+	}{
+		{"no peer", nil, "unknown"},
+		{"address", &peer.Peer{Addr: addr}, "addr:192.0.2.1"},
+		{
+			name: "unverified certificate",
+			peer: &peer.Peer{Addr: addr, AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{alice}}}},
+			want: "addr:192.0.2.1",
+		},
+		{
+			name: "verified certificate",
+			peer: &peer.Peer{Addr: addr, AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{alice}}}}},
+			want: "cert:CN=alice",
+		},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ClientIDKey, "chosen-by-caller"))
+		if tt.peer != nil {
+			ctx = peer.NewContext(ctx, tt.peer)
+		}
+		if got := Caller(ctx); got != tt.want {
+			t.Errorf("%s: Caller = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package recovery provides server interceptors that turn a panicking
+// handler into an INTERNAL error for its own call, instead of a crash of the
+// whole server. Register them first so they also cover the interceptors
+// that follow.
+package recovery
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor recovers panics in unary calls.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor recovers panics in streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs the panic with its stack and returns the error for the
+// caller, which does not include the panic value since it may expose
+// internals, only the incident ID of the log line.
+func recovered(ctx context.Context, method string, r interface{}) error {
+	incident := rpcerr.NewIncident()
+	requestid.Logf(ctx, "panic in %s, incident %s: %v\n%s", method, incident, r, debug.Stack())
+	return rpcerr.MethodDomain(method).InternalIncident(incident)
+}
@@ -0,0 +1,64 @@
+package recovery
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pandadragoon/grpc-go-course/internal/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	resp, err := intercept(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	})
+	if resp != "resp" || err != nil {
+		t.Fatalf("handler without panic: got (%v, %v), want (resp, nil)", resp, err)
+	}
+
+	resp, err = intercept(context.Background(), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if resp != nil || status.Code(err) != codes.Internal {
+		t.Fatalf("panicking handler: got (%v, %v), want (nil, Internal)", resp, err)
+	}
+	if got := status.Convert(err).Message(); strings.Contains(got, "boom") {
+		t.Errorf("panicking handler: message %q exposes the panic", got)
+	}
+	d := rpcerr.Decode(err)
+	if d.Info.GetReason() != rpcerr.ReasonInternal || d.Info.GetDomain() != "test.Service" || d.Info.GetMetadata()["incident"] == "" {
+		t.Errorf("panicking handler: ErrorInfo %v, want an INTERNAL incident of test.Service", d.Info)
+	}
+}
+
+// stream is the server stream of a call without metadata.
+type stream struct{ grpc.ServerStream }
+
+func (stream) Context() context.Context { return context.Background() }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}
+
+	want := status.Error(codes.NotFound, "missing")
+	if err := intercept(nil, nil, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return want
+	}); err != want {
+		t.Fatalf("handler without panic: got %v, want %v", err, want)
+	}
+
+	err := intercept(nil, stream{}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		var m map[string]int
+		m["nil map"]++
+		return nil
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("panicking handler: got %v, want Internal", err)
+	}
+}
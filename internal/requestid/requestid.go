@@ -0,0 +1,151 @@
+// Package requestid correlates a call across clients, servers and their
+// logs by the x-request-id metadata.
+//
+// The server interceptors take the ID a caller sent, or make one up, attach
+// it to the call's context and send it back in the response header and
+// trailer. The client interceptors send the ID of the call being served when
+// there is one, so calls a server makes on behalf of another share its ID,
+// and a new one otherwise. Register the server interceptors first, so the
+// other interceptors see the ID too.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Key is the metadata key of request IDs.
+const Key = "x-request-id"
+
+// maxLen bounds the IDs accepted from callers, which end up in logs.
+const maxLen = 128
+
+type contextKey struct{}
+
+// New returns a random request ID.
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("requestid: reading random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns ctx carrying id as the ID of the call being served.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the ID of the call being served, or "" outside of
+// one.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// NewOutgoingContext returns ctx sending id with the calls made with it,
+// instead of one the client interceptors choose.
+func NewOutgoingContext(ctx context.Context, id string) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(Key, id)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// Logf logs like log.Printf, prefixed with the request ID of ctx when it
+// has one.
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if id := FromContext(ctx); id != "" {
+		msg = "[" + id + "] " + msg
+	}
+	log.Output(2, msg)
+}
+
+// valid reports whether a caller's ID can be used as is: not too long and
+// printable ASCII only, so it cannot forge log lines.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// incoming returns the caller's request ID, or a new one if it sent none or
+// an invalid one.
+func incoming(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(Key); len(ids) > 0 && valid(ids[0]) {
+			return ids[0]
+		}
+	}
+	return New()
+}
+
+// UnaryServerInterceptor attaches the request ID to unary calls.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := incoming(ctx)
+		md := metadata.Pairs(Key, id)
+		grpc.SetHeader(ctx, md)
+		grpc.SetTrailer(ctx, md)
+		return handler(NewContext(ctx, id), req)
+	}
+}
+
+// StreamServerInterceptor attaches the request ID to streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incoming(ss.Context())
+		md := metadata.Pairs(Key, id)
+		ss.SetHeader(md)
+		ss.SetTrailer(md)
+		return handler(srv, &serverStream{ServerStream: ss, ctx: NewContext(ss.Context(), id)})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// outgoing returns ctx sending a request ID: the one already set with
+// NewOutgoingContext, that of the call being served or a new one.
+func outgoing(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(Key)) > 0 {
+		return ctx
+	}
+	id := FromContext(ctx)
+	if id == "" {
+		id = New()
+	}
+	return metadata.AppendToOutgoingContext(ctx, Key, id)
+}
+
+// UnaryClientInterceptor sends a request ID with unary calls. Retries of
+// a call send the same ID.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor sends a request ID with streaming calls.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,136 @@
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"github.com/pandadragoon/grpc-go-course/internal/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// echoServer answers with the request ID its handlers see.
+type echoServer struct {
+	greetpb.UnimplementedGreetServiceServer
+}
+
+func (echoServer) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+	return &greetpb.GreetResponse{Result: requestid.FromContext(ctx)}, nil
+}
+
+func (echoServer) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+	return stream.Send(&greetpb.GreetManyTimesResponse{Result: requestid.FromContext(stream.Context())})
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func start(t *testing.T, opts ...servertest.Option) greetpb.GreetServiceClient {
+	conn := servertest.Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, echoServer{})
+	}, opts...)
+	return greetpb.NewGreetServiceClient(conn)
+}
+
+// call makes a unary and a streaming call with ctx and returns the IDs the
+// handlers saw, checking that the header and trailer of each echo them.
+func call(t *testing.T, c greetpb.GreetServiceClient, ctx context.Context) (unary, stream string) {
+	t.Helper()
+	var header, trailer metadata.MD
+	res, err := c.Greet(ctx, &greetpb.GreetRequest{}, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		t.Fatalf("Greet: %v", err)
+	}
+	unary = res.GetResult()
+	checkEcho(t, "Greet header", header, unary)
+	checkEcho(t, "Greet trailer", trailer, unary)
+
+	s, err := c.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{})
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	header, err = s.Header()
+	if err != nil {
+		t.Fatalf("GreetManyTimes header: %v", err)
+	}
+	msg, err := s.Recv()
+	if err != nil {
+		t.Fatalf("GreetManyTimes: %v", err)
+	}
+	stream = msg.GetResult()
+	checkEcho(t, "GreetManyTimes header", header, stream)
+	if _, err := s.Recv(); err == nil {
+		t.Fatal("GreetManyTimes sent more than one message")
+	}
+	checkEcho(t, "GreetManyTimes trailer", s.Trailer(), stream)
+	return unary, stream
+}
+
+func checkEcho(t *testing.T, what string, md metadata.MD, want string) {
+	t.Helper()
+	if got := md.Get(requestid.Key); len(got) != 1 || got[0] != want {
+		t.Errorf("%s: %s = %q, want %q", what, requestid.Key, got, want)
+	}
+}
+
+func TestServerGeneratesID(t *testing.T) {
+	c := start(t)
+	unary, stream := call(t, c, testContext(t))
+	if unary == "" || stream == "" || unary == stream {
+		t.Errorf("got IDs %q and %q, want two different generated IDs", unary, stream)
+	}
+}
+
+func TestServerAcceptsID(t *testing.T) {
+	c := start(t)
+	for id, keep := range map[string]bool{
+		"abc-123":                true,
+		"two words":              false,
+		strings.Repeat("x", 129): false,
+		strings.Repeat("x", 128): true,
+		"café":                   false,
+	} {
+		ctx := metadata.AppendToOutgoingContext(testContext(t), requestid.Key, id)
+		unary, stream := call(t, c, ctx)
+		if keep && (unary != id || stream != id) {
+			t.Errorf("sent %q, handlers saw %q and %q", id, unary, stream)
+		}
+		if !keep && (unary == id || unary == "" || stream == id || stream == "") {
+			t.Errorf("sent invalid %q, handlers saw %q and %q; want new IDs", id, unary, stream)
+		}
+	}
+}
+
+func TestClientInterceptors(t *testing.T) {
+	c := start(t, servertest.WithDialOptions(
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+	))
+
+	t.Run("chosen", func(t *testing.T) {
+		unary, stream := call(t, c, requestid.NewOutgoingContext(testContext(t), "chosen"))
+		if unary != "chosen" || stream != "chosen" {
+			t.Errorf("handlers saw %q and %q, want chosen", unary, stream)
+		}
+	})
+	t.Run("propagated", func(t *testing.T) {
+		// As when a handler calls another service on behalf of its caller.
+		unary, stream := call(t, c, requestid.NewContext(testContext(t), "served"))
+		if unary != "served" || stream != "served" {
+			t.Errorf("handlers saw %q and %q, want served", unary, stream)
+		}
+	})
+	t.Run("generated", func(t *testing.T) {
+		unary, stream := call(t, c, testContext(t))
+		if len(unary) != 32 || len(stream) != 32 {
+			t.Errorf("handlers saw %q and %q, want generated IDs", unary, stream)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package rpcerr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Details are the error details of a status error that this package knows
+// about. Fields are nil when the error does not carry them.
+type Details struct {
+	Code     codes.Code
+	Message  string
+	Info     *errdetails.ErrorInfo
+	Request  *errdetails.BadRequest
+	Resource *errdetails.ResourceInfo
+	Retry    *errdetails.RetryInfo
+}
+
+// Decode returns the details of err. Errors that are not status errors
+// decode as UNKNOWN with their text as the message.
+func Decode(err error) Details {
+	st := status.Convert(err)
+	d := Details{Code: st.Code(), Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			d.Info = detail
+		case *errdetails.BadRequest:
+			d.Request = detail
+		case *errdetails.ResourceInfo:
+			d.Resource = detail
+		case *errdetails.RetryInfo:
+			d.Retry = detail
+		}
+	}
+	return d
+}
+
+// Reason returns the ErrorInfo reason of err, or "" for none.
+func Reason(err error) string {
+	return Decode(err).Info.GetReason()
+}
+
+// RetryDelay returns how long the server asked to wait before retrying.
+func (d Details) RetryDelay() (time.Duration, bool) {
+	if d.Retry.GetRetryDelay() == nil {
+		return 0, false
+	}
+	return d.Retry.GetRetryDelay().AsDuration(), true
+}
+
+// String presents the details for people: the code, message and reason on
+// the first line, then one line per field violation, resource or metadata
+// entry.
+func (d Details) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s", d.Code, d.Message)
+	if reason := d.Info.GetReason(); reason != "" {
+		fmt.Fprintf(&b, " [%s]", reason)
+	}
+	if delay, ok := d.RetryDelay(); ok {
+		fmt.Fprintf(&b, " (retry in %v)", delay)
+	}
+	for _, v := range d.Request.GetFieldViolations() {
+		// The message is usually the only violation's description.
+		if v.GetDescription() == d.Message {
+			fmt.Fprintf(&b, "\n  field %s", v.GetField())
+			continue
+		}
+		fmt.Fprintf(&b, "\n  field %s: %s", v.GetField(), v.GetDescription())
+	}
+	if r := d.Resource; r != nil {
+		fmt.Fprintf(&b, "\n  %s %s", r.GetResourceType(), r.GetResourceName())
+	}
+	keys := make([]string, 0, len(d.Info.GetMetadata()))
+	for k := range d.Info.GetMetadata() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n  %s: %s", k, d.Info.GetMetadata()[k])
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+// Package rpcerr builds status errors carrying google.rpc error details, and
+// decodes them on the client side.
+//
+// Every error has an ErrorInfo whose reason is a stable UPPER_SNAKE_CASE
+// code of the service's domain, so clients can branch on it instead of
+// parsing messages. Errors about a request field add a BadRequest, errors
+// about a missing resource a ResourceInfo and errors worth retrying later a
+// RetryInfo. Internal errors are logged and reach the client only as an
+// incident ID.
+package rpcerr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	protov1 "github.com/golang/protobuf/proto"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Reasons shared by every domain.
+const (
+	// ReasonInvalidField is the reason of InvalidField errors unless a more
+	// specific one is given.
+	ReasonInvalidField = "INVALID_FIELD"
+	// ReasonInternal is the reason of Internal errors.
+	ReasonInternal = "INTERNAL"
+)
+
+// Domain is the ErrorInfo domain of a service's errors, its fully qualified
+// name such as "blog.BlogService".
+type Domain string
+
+// MethodDomain returns the domain of the service of a full method name such
+// as "/blog.BlogService/ReadBlog", for interceptors.
+func MethodDomain(fullMethod string) Domain {
+	service := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(service, "/"); i >= 0 {
+		service = service[:i]
+	}
+	return Domain(service)
+}
+
+// Info returns an ErrorInfo for reason, with metadata from alternating keys
+// and values.
+func (d Domain) Info(reason string, keyvals ...string) *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: string(d)}
+	if len(keyvals) > 0 {
+		info.Metadata = make(map[string]string, len(keyvals)/2)
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			info.Metadata[keyvals[i]] = keyvals[i+1]
+		}
+	}
+	return info
+}
+
+// Error returns a code error with msg and an ErrorInfo for reason, followed
+// by any other details.
+func (d Domain) Error(code codes.Code, reason, msg string, details ...proto.Message) error {
+	return New(code, msg, append([]proto.Message{d.Info(reason)}, details...)...)
+}
+
+// Errorf is Error with a formatted message and no other details.
+func (d Domain) Errorf(code codes.Code, reason, format string, args ...interface{}) error {
+	return d.Error(code, reason, fmt.Sprintf(format, args...))
+}
+
+// InvalidField returns an INVALID_ARGUMENT error about field of the request,
+// with a BadRequest field violation. reason defaults to ReasonInvalidField.
+func (d Domain) InvalidField(reason, field, description string) error {
+	if reason == "" {
+		reason = ReasonInvalidField
+	}
+	return d.Error(codes.InvalidArgument, reason, description, FieldViolation(field, description))
+}
+
+// NotFound returns a NOT_FOUND error about the resource of resourceType
+// named name, with a ResourceInfo.
+func (d Domain) NotFound(reason, resourceType, name, msg string) error {
+	return d.Error(codes.NotFound, reason, msg, &errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Description:  msg,
+	})
+}
+
+// Exhausted returns a RESOURCE_EXHAUSTED error asking to retry after wait,
+// with a RetryInfo.
+func (d Domain) Exhausted(reason, msg string, wait time.Duration) error {
+	return d.Error(codes.ResourceExhausted, reason, msg, &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(wait),
+	})
+}
+
+// Internal logs err, with the request ID of ctx, and returns an INTERNAL
+// error that does not include it, since it may expose internals such as
+// database errors. The two share an incident ID, so an operator can find
+// the log line from a report.
+func (d Domain) Internal(ctx context.Context, err error) error {
+	incident := NewIncident()
+	requestid.Logf(ctx, "%s: internal error, incident %s: %v", d, incident, err)
+	return d.InternalIncident(incident)
+}
+
+// InternalIncident returns the INTERNAL error for an incident already logged
+// with the given ID.
+func (d Domain) InternalIncident(incident string) error {
+	msg := "internal error"
+	if incident != "" {
+		msg += " (incident " + incident + ")"
+	}
+	return New(codes.Internal, msg, d.Info(ReasonInternal, "incident", incident))
+}
+
+// NewIncident returns a random ID for an internal error.
+func NewIncident() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// FieldViolation returns a BadRequest with one violation.
+func FieldViolation(field, description string) *errdetails.BadRequest {
+	return &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	}
+}
+
+// New returns a code error with msg and details. Should the details not
+// encode, the error goes without them rather than not at all.
+func New(code codes.Code, msg string, details ...proto.Message) error {
+	st := status.New(code, msg)
+	v1 := make([]protov1.Message, len(details))
+	for i, d := range details {
+		v1[i] = protov1.MessageV1(d)
+	}
+	if withDetails, err := st.WithDetails(v1...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,90 @@
+package rpcerr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testDomain Domain = "test.TestService"
+
+func TestMethodDomain(t *testing.T) {
+	for method, want := range map[string]Domain{
+		"/blog.BlogService/ReadBlog": "blog.BlogService",
+		"blog.BlogService/ReadBlog":  "blog.BlogService",
+		"nomethod":                   "nomethod",
+	} {
+		if got := MethodDomain(method); got != want {
+			t.Errorf("MethodDomain(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
+
+func TestInvalidField(t *testing.T) {
+	err := testDomain.InvalidField("", "count", "count must be positive")
+	d := Decode(err)
+	if d.Code != codes.InvalidArgument || d.Message != "count must be positive" {
+		t.Errorf("got %v, want InvalidArgument with the description", err)
+	}
+	if d.Info.GetReason() != ReasonInvalidField || d.Info.GetDomain() != string(testDomain) {
+		t.Errorf("ErrorInfo = %v, want %s in %s", d.Info, ReasonInvalidField, testDomain)
+	}
+	if v := d.Request.GetFieldViolations(); len(v) != 1 || v[0].GetField() != "count" {
+		t.Errorf("BadRequest = %v, want one violation of count", d.Request)
+	}
+	if got := d.String(); got != "InvalidArgument: count must be positive [INVALID_FIELD]\n  field count" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	err := testDomain.NotFound("THING_NOT_FOUND", "test.Thing", "42", "no thing 42")
+	d := Decode(err)
+	if d.Code != codes.NotFound || Reason(err) != "THING_NOT_FOUND" {
+		t.Errorf("got %v with reason %q, want NotFound THING_NOT_FOUND", err, Reason(err))
+	}
+	if d.Resource.GetResourceType() != "test.Thing" || d.Resource.GetResourceName() != "42" {
+		t.Errorf("ResourceInfo = %v, want test.Thing 42", d.Resource)
+	}
+	if got := d.String(); !strings.HasSuffix(got, "\n  test.Thing 42") {
+		t.Errorf("String() = %q, want the resource", got)
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	err := testDomain.Exhausted("SLOW_DOWN", "too fast", 1500*time.Millisecond)
+	d := Decode(err)
+	if delay, ok := d.RetryDelay(); !ok || delay != 1500*time.Millisecond {
+		t.Errorf("RetryDelay() = %v, %v; want 1.5s", delay, ok)
+	}
+	if got := d.String(); got != "ResourceExhausted: too fast [SLOW_DOWN] (retry in 1.5s)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestInternal(t *testing.T) {
+	err := testDomain.Internal(context.Background(), errors.New("password=hunter2"))
+	d := Decode(err)
+	if d.Code != codes.Internal || strings.Contains(d.Message, "hunter2") {
+		t.Errorf("got %v, want Internal without the cause", err)
+	}
+	incident := d.Info.GetMetadata()["incident"]
+	if d.Info.GetReason() != ReasonInternal || incident == "" || !strings.Contains(d.Message, incident) {
+		t.Errorf("got %v with ErrorInfo %v, want an incident ID in both", err, d.Info)
+	}
+}
+
+func TestDecodePlain(t *testing.T) {
+	d := Decode(errors.New("boom"))
+	if d.Code != codes.Unknown || d.Message != "boom" || d.Info != nil {
+		t.Errorf("Decode(plain error) = %+v", d)
+	}
+	if _, ok := Decode(status.Error(codes.NotFound, "gone")).RetryDelay(); ok {
+		t.Error("RetryDelay() of an error without RetryInfo succeeded")
+	}
+}
@@ -0,0 +1,155 @@
+// Package servertest starts the course services on in-memory listeners, so
+// tests can talk to them through real gRPC clients without a network or a
+// database. Servers get the same request IDs, compressors and panic recovery
+// as the real ones and are stopped when the test ends.
+package servertest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/pandadragoon/grpc-go-course/blog/blogpb"
+	"github.com/pandadragoon/grpc-go-course/blog/blogserver"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorpb"
+	"github.com/pandadragoon/grpc-go-course/calculator/calculatorserver"
+	"github.com/pandadragoon/grpc-go-course/greet/greetpb"
+	"github.com/pandadragoon/grpc-go-course/greet/greetserver"
+	_ "github.com/pandadragoon/grpc-go-course/internal/compression" // gzip and snappy
+	"github.com/pandadragoon/grpc-go-course/internal/recovery"
+	"github.com/pandadragoon/grpc-go-course/internal/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1 << 20
+
+type options struct {
+	serverOpts []grpc.ServerOption
+	dialOpts   []grpc.DialOption
+}
+
+// Option configures Start.
+type Option func(*options)
+
+// WithServerOptions passes extra options to grpc.NewServer. Interceptors
+// added with grpc.ChainUnaryInterceptor or grpc.ChainStreamInterceptor run
+// inside the recovery ones.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(o *options) {
+		o.serverOpts = append(o.serverOpts, opts...)
+	}
+}
+
+// WithDialOptions passes extra options to grpc.Dial.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOpts = append(o.dialOpts, opts...)
+	}
+}
+
+// Start serves the services register adds to a new server, and returns a
+// connection to it.
+func Start(t testing.TB, register func(*grpc.Server), opts ...Option) *grpc.ClientConn {
+	t.Helper()
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lis := Serve(t, register, opts...)
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithInsecure(),
+	}, o.dialOpts...)
+	conn, err := grpc.Dial("bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("dialing test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Serve serves the services register adds to a new server and returns its
+// listener, for tests that dial it themselves, such as several servers
+// behind one client. Dial options are ignored.
+func Serve(t testing.TB, register func(*grpc.Server), opts ...Option) *bufconn.Listener {
+	t.Helper()
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lis := bufconn.Listen(bufSize)
+	serverOpts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(requestid.UnaryServerInterceptor(), recovery.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(requestid.StreamServerInterceptor(), recovery.StreamServerInterceptor()),
+	}, o.serverOpts...)
+	s := grpc.NewServer(serverOpts...)
+	register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis
+}
+
+// Greet starts a greet service.
+func Greet(t testing.TB, opts ...Option) greetpb.GreetServiceClient {
+	t.Helper()
+	conn := Start(t, func(s *grpc.Server) {
+		greetpb.RegisterGreetServiceServer(s, greetserver.New())
+	}, opts...)
+	return greetpb.NewGreetServiceClient(conn)
+}
+
+// Calculator starts a calculator service without a result cache.
+func Calculator(t testing.TB, opts ...Option) calculatorpb.CalculatorServiceClient {
+	t.Helper()
+	conn := Start(t, func(s *grpc.Server) {
+		calculatorpb.RegisterCalculatorServiceServer(s, calculatorserver.New(nil))
+	}, opts...)
+	return calculatorpb.NewCalculatorServiceClient(conn)
+}
+
+// Blog starts a blog service on an empty in-memory store, which is returned
+// for the test to fill or inspect.
+func Blog(t testing.TB, opts ...Option) (blogpb.BlogServiceClient, *blogserver.MemoryStore) {
+	t.Helper()
+	store := blogserver.NewMemoryStore()
+	conn := Start(t, func(s *grpc.Server) {
+		blogpb.RegisterBlogServiceServer(s, blogserver.New(store))
+	}, opts...)
+	return blogpb.NewBlogServiceClient(conn), store
+}
+
+// Payloads is a stats handler, for grpc.WithStatsHandler, that counts the
+// messages received over a connection.
+type Payloads struct {
+	mu                    sync.Mutex
+	count, length, onWire int
+}
+
+// Received returns how many messages were received, their total size and
+// how many bytes they took on the wire, compressed and framed.
+func (p *Payloads) Received() (count, length, onWire int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count, p.length, p.onWire
+}
+
+func (p *Payloads) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InPayload); ok {
+		p.mu.Lock()
+		p.count++
+		p.length += in.Length
+		p.onWire += in.WireLength
+		p.mu.Unlock()
+	}
+}
+
+func (*Payloads) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context   { return ctx }
+func (*Payloads) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (*Payloads) HandleConn(context.Context, stats.ConnStats)                       {}
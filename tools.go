@@ -0,0 +1,11 @@
+//go:build tools
+// +build tools
+
+// Package tools pins the versions of the code generators in go.mod, so
+// every checkout generates the same code. See internal/cmd/protogen.
+package tools
+
+import (
+	_ "google.golang.org/grpc/cmd/protoc-gen-go-grpc"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go"
+)